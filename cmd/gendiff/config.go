@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	sourceDefault = "default"
+	sourceEnv     = "env"
+	sourceFlag    = "flag"
+	sourceFile    = "file"
+)
+
+var configFileNames = []string{".gendiffrc", "gendiff.yaml"}
+
+type fileSettings struct {
+	Format      *string           `yaml:"format"`
+	Ignore      []string          `yaml:"ignore"`
+	Only        []string          `yaml:"only"`
+	Comparators map[string]string `yaml:"comparators"`
+	Redact      *bool             `yaml:"redact"`
+	RedactPaths []string          `yaml:"redact-paths"`
+	Color       *string           `yaml:"color"`
+	Width       *int              `yaml:"width"`
+	Positions   *bool             `yaml:"positions"`
+}
+
+type configFile struct {
+	fileSettings `yaml:",inline"`
+	Profiles     map[string]fileSettings `yaml:"profiles"`
+}
+
+type settings struct {
+	Config      string            `yaml:"config"`
+	Profile     string            `yaml:"profile"`
+	Format      string            `yaml:"format"`
+	Ignore      []string          `yaml:"ignore"`
+	Only        []string          `yaml:"only"`
+	Comparators map[string]string `yaml:"comparators"`
+	Redact      bool              `yaml:"redact"`
+	RedactPaths []string          `yaml:"redact-paths"`
+	Color       string            `yaml:"color"`
+	Width       int               `yaml:"width"`
+	Positions   bool              `yaml:"positions"`
+	RedactKey   string            `yaml:"-"`
+
+	sources map[string]string
+}
+
+func findConfig(dir string) (string, error) {
+	for {
+		for _, name := range configFileNames {
+			path := filepath.Join(dir, name)
+			info, err := os.Stat(path)
+			if err == nil && !info.IsDir() {
+				return path, nil
+			}
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return "", err
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+func readConfig(path string) (*configFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config %s: %w", path, err)
+	}
+
+	var config configFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parsing config %s: %w", path, err)
+	}
+	return &config, nil
+}
+
+func (c *configFile) profile(name string) (fileSettings, error) {
+	base := c.fileSettings
+	if name == "" {
+		return base, nil
+	}
+
+	profile, ok := c.Profiles[name]
+	if !ok {
+		names := make([]string, 0, len(c.Profiles))
+		for profileName := range c.Profiles {
+			names = append(names, profileName)
+		}
+		sort.Strings(names)
+		return base, fmt.Errorf("unknown profile %q (available: %v)", name, names)
+	}
+
+	if profile.Format != nil {
+		base.Format = profile.Format
+	}
+	if profile.Ignore != nil {
+		base.Ignore = profile.Ignore
+	}
+	if profile.Only != nil {
+		base.Only = profile.Only
+	}
+	if profile.Comparators != nil {
+		base.Comparators = profile.Comparators
+	}
+	if profile.Redact != nil {
+		base.Redact = profile.Redact
+	}
+	if profile.RedactPaths != nil {
+		base.RedactPaths = profile.RedactPaths
+	}
+	if profile.Color != nil {
+		base.Color = profile.Color
+	}
+	if profile.Width != nil {
+		base.Width = profile.Width
+	}
+	if profile.Positions != nil {
+		base.Positions = profile.Positions
+	}
+	return base, nil
+}
+
+func loadSettings(cmd *cli.Command) (*settings, error) {
+	s := &settings{
+		Profile: cmd.String("profile"),
+		sources: map[string]string{},
+	}
+
+	s.Config = cmd.String("config")
+	if s.Config == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		if s.Config, err = findConfig(wd); err != nil {
+			return nil, err
+		}
+	}
+
+	var file fileSettings
+	if s.Config != "" {
+		config, err := readConfig(s.Config)
+		if err != nil {
+			return nil, err
+		}
+		if file, err = config.profile(s.Profile); err != nil {
+			return nil, fmt.Errorf("config %s: %w", s.Config, err)
+		}
+	} else if s.Profile != "" {
+		return nil, fmt.Errorf("profile %q requested but no config file found", s.Profile)
+	}
+
+	s.Format = resolve(cmd, s.sources, "format", file.Format, cmd.String)
+	s.Ignore = resolveSlice(cmd, s.sources, "ignore", file.Ignore)
+	s.Only = resolveSlice(cmd, s.sources, "only", file.Only)
+	s.Comparators = resolveMap(cmd, s.sources, "comparator", file.Comparators)
+	s.Redact = resolve(cmd, s.sources, "redact", file.Redact, cmd.Bool)
+	s.RedactPaths = resolveSlice(cmd, s.sources, "redact-path", file.RedactPaths)
+	s.Color = resolve(cmd, s.sources, "color", file.Color, cmd.String)
+	s.Width = resolve(cmd, s.sources, "width", file.Width, cmd.Int)
+	s.Positions = resolve(cmd, s.sources, "positions", file.Positions, cmd.Bool)
+	s.RedactKey = cmd.String("redact-key")
+	return s, nil
+}
+
+func resolve[T any](cmd *cli.Command, sources map[string]string, name string, file *T, get func(string) T) T {
+	if !cmd.IsSet(name) && file != nil {
+		sources[name] = sourceFile
+		return *file
+	}
+	sources[name] = flagSource(cmd, name)
+	return get(name)
+}
+
+func resolveSlice(cmd *cli.Command, sources map[string]string, name string, file []string) []string {
+	var fromFile *[]string
+	if file != nil {
+		fromFile = &file
+	}
+	return resolve(cmd, sources, name, fromFile, cmd.StringSlice)
+}
+
+func resolveMap(cmd *cli.Command, sources map[string]string, name string, file map[string]string) map[string]string {
+	var fromFile *map[string]string
+	if file != nil {
+		fromFile = &file
+	}
+	return resolve(cmd, sources, name, fromFile, cmd.StringMap)
+}
+
+func flagSource(cmd *cli.Command, name string) string {
+	if !cmd.IsSet(name) {
+		return sourceDefault
+	}
+	if onCommandLine(cmd, name) {
+		return sourceFlag
+	}
+	return sourceEnv
+}
+
+func onCommandLine(cmd *cli.Command, name string) bool {
+	var names []string
+	for _, flag := range cmd.Root().Flags {
+		if slices.Contains(flag.Names(), name) {
+			names = flag.Names()
+		}
+	}
+
+	for _, arg := range os.Args[1:] {
+		if arg == "--" {
+			return false
+		}
+		for _, flagName := range names {
+			prefix := "--" + flagName
+			if len(flagName) == 1 {
+				prefix = "-" + flagName
+			}
+			if arg == prefix || strings.HasPrefix(arg, prefix+"=") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *settings) show() (string, error) {
+	var node yaml.Node
+	if err := node.Encode(s); err != nil {
+		return "", err
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		name := key.Value
+		switch name {
+		case "comparators":
+			name = "comparator"
+		case "redact-paths":
+			name = "redact-path"
+		}
+		if source, ok := s.sources[name]; ok {
+			if value := node.Content[i+1]; len(value.Content) == 0 {
+				value.LineComment = source
+			} else {
+				key.LineComment = source
+			}
+		}
+	}
+
+	var out strings.Builder
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
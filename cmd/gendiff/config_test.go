@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+const testConfig = `format: plain
+ignore: [metadata]
+comparators:
+  spec.replicas: numeric
+width: 100
+profiles:
+  k8s:
+    format: json
+    only: [spec]
+`
+
+func runCommand(t *testing.T, action cli.ActionFunc, args ...string) string {
+	t.Helper()
+
+	args = append([]string{"gendiff"}, args...)
+	saved := os.Args
+	os.Args = args
+	t.Cleanup(func() { os.Args = saved })
+
+	var out bytes.Buffer
+	cmd := newCommand()
+	cmd.Writer = &out
+	if action != nil {
+		cmd.Action = action
+	}
+	require.NoError(t, cmd.Run(context.Background(), args))
+	return out.String()
+}
+
+func loadTestSettings(t *testing.T, args ...string) (*settings, error) {
+	t.Helper()
+
+	var s *settings
+	var err error
+	runCommand(t, func(ctx context.Context, cmd *cli.Command) error {
+		s, err = loadSettings(cmd)
+		return nil
+	}, args...)
+	return s, err
+}
+
+func writeConfig(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestLoadSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		env     map[string]string
+		args    []string
+		format  string
+		only    []string
+		ignore  []string
+		width   int
+		sources map[string]string
+	}{
+		{
+			name:    "defaults without a config file",
+			format:  "stylish",
+			sources: map[string]string{"format": sourceDefault, "width": sourceDefault},
+		},
+		{
+			name:    "config file",
+			config:  testConfig,
+			format:  "plain",
+			ignore:  []string{"metadata"},
+			width:   100,
+			sources: map[string]string{"format": sourceFile, "ignore": sourceFile, "only": sourceDefault},
+		},
+		{
+			name:    "profile overrides the top level",
+			config:  testConfig,
+			args:    []string{"--profile", "k8s"},
+			format:  "json",
+			only:    []string{"spec"},
+			ignore:  []string{"metadata"},
+			width:   100,
+			sources: map[string]string{"format": sourceFile, "only": sourceFile, "ignore": sourceFile},
+		},
+		{
+			name:    "profile from the environment",
+			config:  testConfig,
+			env:     map[string]string{"GENDIFF_PROFILE": "k8s"},
+			format:  "json",
+			only:    []string{"spec"},
+			ignore:  []string{"metadata"},
+			width:   100,
+			sources: map[string]string{"only": sourceFile},
+		},
+		{
+			name:    "env wins over the file",
+			config:  testConfig,
+			env:     map[string]string{"GENDIFF_FORMAT": "yaml", "GENDIFF_WIDTH": "80"},
+			format:  "yaml",
+			ignore:  []string{"metadata"},
+			width:   80,
+			sources: map[string]string{"format": sourceEnv, "width": sourceEnv, "ignore": sourceFile},
+		},
+		{
+			name:    "flag wins over env and file",
+			config:  testConfig,
+			env:     map[string]string{"GENDIFF_FORMAT": "yaml", "GENDIFF_IGNORE": "status"},
+			args:    []string{"--format", "sarif", "--ignore", "spec"},
+			format:  "sarif",
+			ignore:  []string{"spec"},
+			width:   100,
+			sources: map[string]string{"format": sourceFlag, "ignore": sourceFlag, "width": sourceFile},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.config != "" {
+				writeConfig(t, dir, ".gendiffrc", tt.config)
+			}
+			t.Chdir(dir)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			s, err := loadTestSettings(t, tt.args...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.format, s.Format)
+			assert.ElementsMatch(t, tt.only, s.Only)
+			assert.ElementsMatch(t, tt.ignore, s.Ignore)
+			assert.Equal(t, tt.width, s.Width)
+			for name, source := range tt.sources {
+				assert.Equal(t, source, s.sources[name], name)
+			}
+		})
+	}
+}
+
+func TestLoadSettingsDiscovery(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "a", "b")
+	require.NoError(t, os.MkdirAll(nested, 0o755))
+	writeConfig(t, root, "gendiff.yaml", "format: yaml\n")
+	t.Chdir(nested)
+
+	s, err := loadTestSettings(t)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "gendiff.yaml"), s.Config)
+	assert.Equal(t, "yaml", s.Format)
+
+	closer := writeConfig(t, filepath.Join(root, "a"), ".gendiffrc", "format: plain\n")
+	s, err = loadTestSettings(t)
+	require.NoError(t, err)
+	assert.Equal(t, closer, s.Config)
+	assert.Equal(t, "plain", s.Format)
+
+	explicit := writeConfig(t, t.TempDir(), "custom.yaml", "format: json\n")
+	s, err = loadTestSettings(t, "--config", explicit)
+	require.NoError(t, err)
+	assert.Equal(t, explicit, s.Config)
+	assert.Equal(t, "json", s.Format)
+}
+
+func TestLoadSettingsErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		args   []string
+		err    string
+	}{
+		{name: "unknown key", config: "formt: plain\n", err: "field formt not found"},
+		{name: "unknown profile key", config: "profiles:\n  k8s:\n    colour: never\n", err: "field colour not found"},
+		{name: "unknown profile", config: testConfig, args: []string{"--profile", "prod"}, err: `unknown profile "prod" (available: [k8s])`},
+		{name: "profile without a config file", args: []string{"--profile", "k8s"}, err: "no config file found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.config != "" {
+				writeConfig(t, dir, ".gendiffrc", tt.config)
+			}
+			t.Chdir(dir)
+
+			_, err := loadTestSettings(t, tt.args...)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+func TestConfigShow(t *testing.T) {
+	dir := t.TempDir()
+	writeConfig(t, dir, ".gendiffrc", testConfig)
+	t.Chdir(dir)
+	t.Setenv("GENDIFF_COLOR", "never")
+
+	out := runCommand(t, nil, "--format", "json", "config", "show")
+	assert.Contains(t, out, "format: json # flag\n")
+	assert.Contains(t, out, "color: never # env\n")
+	assert.Contains(t, out, "width: 100 # file\n")
+	assert.Contains(t, out, "positions: false # default\n")
+	assert.Contains(t, out, "ignore: # file\n  - metadata\n")
+	assert.Contains(t, out, "comparators: # file\n  spec.replicas: numeric\n")
+}
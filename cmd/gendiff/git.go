@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	gendiff "github.com/jobsboris27/go-project-244"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+)
+
+const (
+	devNull        = "/dev/null"
+	gitDriverName  = "gendiff"
+	gitAttributes  = ".gitattributes"
+	defaultCommand = "gendiff git-driver"
+)
+
+func gitDriverCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "git-driver",
+		Usage:     "act as git's external diff driver (see install-git-driver)",
+		ArgsUsage: "path old-file old-hex old-mode new-file new-hex new-mode",
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			out := cmd.Root().Writer
+			args := cmd.Args().Slice()
+			if len(args) == 1 {
+				fmt.Fprintf(out, "* Unmerged path %s\n", args[0])
+				return nil
+			}
+			if len(args) < 7 {
+				return cli.Exit("Error: git-driver expects 7 arguments from git", exitTrouble)
+			}
+
+			settings, err := loadSettings(cmd)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("Error: %v", err), exitTrouble)
+			}
+			if err := gitDiff(out, args, settings); err != nil {
+				return cli.Exit(fmt.Sprintf("Error: %v", err), exitTrouble)
+			}
+			return nil
+		},
+	}
+}
+
+func gitDiff(out io.Writer, args []string, settings *settings) error {
+	path, oldFile, oldMode, newFile, newMode := args[0], args[1], args[3], args[4], args[6]
+
+	fmt.Fprintf(out, "gendiff a/%s b/%s\n", path, path)
+	switch {
+	case oldFile == devNull:
+		fmt.Fprintf(out, "new file mode %s\n", newMode)
+	case newFile == devNull:
+		fmt.Fprintf(out, "deleted file mode %s\n", oldMode)
+	case oldMode != newMode:
+		fmt.Fprintf(out, "old mode %s\nnew mode %s\n", oldMode, newMode)
+	}
+
+	oldDoc, err := gitDocument(path, oldFile)
+	if err != nil {
+		return err
+	}
+	newDoc, err := gitDocument(path, newFile)
+	if err != nil {
+		return err
+	}
+
+	color, err := resolveColor(settings.Color, os.Stdout)
+	if err != nil {
+		return err
+	}
+	opts := gendiff.Options{
+		Ignore:      settings.Ignore,
+		Only:        settings.Only,
+		Comparators: settings.Comparators,
+		Color:       color,
+		Width:       resolveWidth(settings.Width, os.Stdout),
+		Positions:   settings.Positions,
+		ShowSecrets: !settings.Redact,
+		RedactPaths: settings.RedactPaths,
+		RedactKey:   settings.RedactKey,
+		OldFile:     "a/" + path,
+		NewFile:     "b/" + path,
+	}
+
+	// A file git cannot parse should not abort the whole `git diff`, so the
+	// error is reported in place of its diff.
+	diff, err := gendiff.CompareDocuments(oldDoc, newDoc, opts)
+	if err != nil {
+		fmt.Fprintf(out, "Error: %v\n", err)
+		return nil
+	}
+	if err := gendiff.RenderWithOptions(out, diff, settings.Format, opts); err != nil {
+		return err
+	}
+	fmt.Fprintln(out)
+	return nil
+}
+
+// gitDocument reads one side of the diff. Git names its temporary files after
+// the blob, so the parser is picked from the path in the work tree instead.
+func gitDocument(path, file string) (gendiff.Document, error) {
+	if file == devNull {
+		return gendiff.Document{Name: path, Type: "json", Content: []byte("{}")}, nil
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return gendiff.Document{}, err
+	}
+	return gendiff.Document{Name: path, Content: content}, nil
+}
+
+func installGitDriverCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "install-git-driver",
+		Usage: "make `git diff` use gendiff for supported files in this repository",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "command",
+				Value: defaultCommand,
+				Usage: "command git runs as the diff driver",
+			},
+			&cli.BoolFlag{
+				Name:  "print",
+				Usage: "print the .gitattributes and git config snippets instead of writing them",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			out := cmd.Root().Writer
+			attributes := gitAttributeLines()
+			command := cmd.String("command")
+
+			if cmd.Bool("print") {
+				fmt.Fprintf(out, "# %s\n%s\n", gitAttributes, strings.Join(attributes, "\n"))
+				fmt.Fprintf(out, "\n# .git/config\n[diff %q]\n\tcommand = %s\n", gitDriverName, command)
+				return nil
+			}
+
+			root, err := gitOutput("rev-parse", "--show-toplevel")
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("Error: not inside a git repository: %v", err), exitTrouble)
+			}
+			if _, err := gitOutput("config", "--local", "diff."+gitDriverName+".command", command); err != nil {
+				return cli.Exit(fmt.Sprintf("Error: %v", err), exitTrouble)
+			}
+
+			path := filepath.Join(root, gitAttributes)
+			added, err := appendMissingLines(path, attributes)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("Error: %v", err), exitTrouble)
+			}
+			fmt.Fprintf(out, "set diff.%s.command = %s\n", gitDriverName, command)
+			fmt.Fprintf(out, "added %d line(s) to %s\n", added, path)
+			return nil
+		},
+	}
+}
+
+func gitAttributeLines() []string {
+	var lines []string
+	for _, ext := range gendiff.Extensions() {
+		lines = append(lines, fmt.Sprintf("*%s diff=%s", ext, gitDriverName))
+	}
+	return lines
+}
+
+func appendMissingLines(path string, lines []string) (int, error) {
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return 0, err
+	}
+
+	present := map[string]bool{}
+	for _, line := range strings.Split(string(existing), "\n") {
+		present[strings.TrimSpace(line)] = true
+	}
+
+	var missing bytes.Buffer
+	if len(existing) > 0 && !bytes.HasSuffix(existing, []byte("\n")) {
+		missing.WriteString("\n")
+	}
+	added := 0
+	for _, line := range lines {
+		if !present[line] {
+			missing.WriteString(line + "\n")
+			added++
+		}
+	}
+	if added == 0 {
+		return 0, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	_, err = file.Write(missing.Bytes())
+	return added, err
+}
+
+func gitOutput(args ...string) (string, error) {
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
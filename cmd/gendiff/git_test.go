@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitDiff(t *testing.T) {
+	dir := t.TempDir()
+	oldFile := writeConfig(t, dir, "old.tmp", `{"replicas": 1, "name": "api"}`)
+	newFile := writeConfig(t, dir, "new.tmp", "replicas: 2\nname: api\n")
+	settings := &settings{Format: "plain", Color: colorNever}
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name: "modified",
+			args: []string{"values.yaml", oldFile, "1111111", "100644", newFile, "2222222", "100644"},
+			expected: "gendiff a/values.yaml b/values.yaml\n" +
+				"Property 'replicas' was updated. From 1 to 2\n",
+		},
+		{
+			name: "mode change",
+			args: []string{"values.yaml", oldFile, "1111111", "100644", newFile, "2222222", "100755"},
+			expected: "gendiff a/values.yaml b/values.yaml\nold mode 100644\nnew mode 100755\n" +
+				"Property 'replicas' was updated. From 1 to 2\n",
+		},
+		{
+			name: "added file",
+			args: []string{"values.yaml", devNull, ".", ".", newFile, "2222222", "100644"},
+			expected: "gendiff a/values.yaml b/values.yaml\nnew file mode 100644\n" +
+				"Property 'name' was added with value: 'api'\n" +
+				"Property 'replicas' was added with value: 2\n",
+		},
+		{
+			name: "deleted file",
+			args: []string{"values.yaml", oldFile, "1111111", "100644", devNull, ".", "."},
+			expected: "gendiff a/values.yaml b/values.yaml\ndeleted file mode 100644\n" +
+				"Property 'name' was removed\n" +
+				"Property 'replicas' was removed\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			require.NoError(t, gitDiff(&out, tt.args, settings))
+			assert.Equal(t, tt.expected, out.String())
+		})
+	}
+
+	var out bytes.Buffer
+	badFile := writeConfig(t, dir, "bad.tmp", "{")
+	require.NoError(t, gitDiff(&out, []string{"values.json", oldFile, "1111111", "100644", badFile, "2222222", "100644"}, settings))
+	assert.True(t, strings.HasPrefix(out.String(), "gendiff a/values.json b/values.json\nError: "), out.String())
+}
+
+func TestGitDriverCommand(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	oldFile := writeConfig(t, dir, "old.tmp", `{"replicas": 1}`)
+	newFile := writeConfig(t, dir, "new.tmp", `{"replicas": 2}`)
+
+	out := runCommand(t, nil, "--format", "plain", "--color", "never",
+		"git-driver", "values.json", oldFile, "1111111", "100644", newFile, "2222222", "100644")
+	assert.Equal(t, "gendiff a/values.json b/values.json\nProperty 'replicas' was updated. From 1 to 2\n", out)
+
+	out = runCommand(t, nil, "git-driver", "values.json")
+	assert.Equal(t, "* Unmerged path values.json\n", out)
+}
+
+func TestAppendMissingLines(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), gitAttributes, "*.png binary")
+	lines := []string{"*.json diff=gendiff", "*.png binary", "*.yaml diff=gendiff"}
+
+	added, err := appendMissingLines(path, lines)
+	require.NoError(t, err)
+	assert.Equal(t, 2, added)
+
+	added, err = appendMissingLines(path, lines)
+	require.NoError(t, err)
+	assert.Equal(t, 0, added)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "*.png binary\n*.json diff=gendiff\n*.yaml diff=gendiff\n", string(data))
+
+	created := filepath.Join(t.TempDir(), gitAttributes)
+	added, err = appendMissingLines(created, lines[:1])
+	require.NoError(t, err)
+	assert.Equal(t, 1, added)
+	data, err = os.ReadFile(created)
+	require.NoError(t, err)
+	assert.Equal(t, "*.json diff=gendiff\n", string(data))
+}
+
+func TestInstallGitDriver(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, exec.Command("git", "init", "-q", dir).Run())
+	nested := filepath.Join(dir, "charts")
+	require.NoError(t, os.MkdirAll(nested, 0o755))
+	t.Chdir(nested)
+
+	out := runCommand(t, nil, "install-git-driver", "--command", "gendiff-test git-driver")
+	lines := gitAttributeLines()
+	root, err := gitOutput("rev-parse", "--show-toplevel")
+	require.NoError(t, err)
+	attributes := filepath.Join(root, gitAttributes)
+	assert.Contains(t, out, "set diff.gendiff.command = gendiff-test git-driver\n")
+	assert.Contains(t, out, fmt.Sprintf("added %d line(s) to %s\n", len(lines), attributes))
+
+	command, err := gitOutput("config", "--local", "diff.gendiff.command")
+	require.NoError(t, err)
+	assert.Equal(t, "gendiff-test git-driver", command)
+
+	data, err := os.ReadFile(attributes)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Join(lines, "\n")+"\n", string(data))
+
+	out = runCommand(t, nil, "install-git-driver", "--command", "gendiff-test git-driver")
+	assert.Contains(t, out, "added 0 line(s)")
+
+	out = runCommand(t, nil, "install-git-driver", "--print")
+	assert.Contains(t, out, "# .gitattributes\n"+lines[0]+"\n")
+	assert.Contains(t, out, "[diff \"gendiff\"]\n\tcommand = "+defaultCommand+"\n")
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	gendiff "github.com/jobsboris27/go-project-244"
+	"os"
+)
+
+const githubStepSummaryEnv = "GITHUB_STEP_SUMMARY"
+
+// writeStepSummary appends the diff as markdown to the job summary file GitHub
+// Actions names in GITHUB_STEP_SUMMARY. It does nothing outside Actions, where
+// path is empty.
+func writeStepSummary(path string, diff gendiff.Diff) error {
+	if path == "" {
+		return nil
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("writing step summary: %w", err)
+	}
+
+	if _, err = fmt.Fprint(file, "## gendiff\n\n"); err == nil {
+		err = gendiff.Render(file, diff, "markdown")
+	}
+	if err == nil {
+		_, err = fmt.Fprintln(file)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("writing step summary: %w", err)
+	}
+	return nil
+}
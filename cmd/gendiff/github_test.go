@@ -0,0 +1,26 @@
+package main
+
+import (
+	gendiff "github.com/jobsboris27/go-project-244"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteStepSummary(t *testing.T) {
+	diff, err := gendiff.CompareFiles("../../testdata/fixture/nested1.json", "../../testdata/fixture/nested2.json", gendiff.Options{})
+	assert.NoError(t, err)
+
+	summary := t.TempDir() + "/summary.md"
+	assert.NoError(t, writeStepSummary(summary, diff))
+	assert.NoError(t, writeStepSummary(summary, diff))
+
+	data, err := os.ReadFile(summary)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(data), "## gendiff\n\n"))
+	assert.Contains(t, string(data), "| `group1.baz` | updated | `'bas'` | `'bars'` |")
+
+	assert.NoError(t, writeStepSummary("", diff))
+}
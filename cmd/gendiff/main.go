@@ -1,24 +1,137 @@
 package main
 
 import (
-	"code"
 	"context"
 	"fmt"
-	"log"
+	gendiff "github.com/jobsboris27/go-project-244"
 	"os"
+	"slices"
+	"strings"
 
 	"github.com/urfave/cli/v3"
 )
 
+const (
+	exitSame      = 0
+	exitDifferent = 1
+	exitTrouble   = 2
+)
+
 func main() {
-	cmd := &cli.Command{
-		Name:  "gendiff",
-		Usage: "Compares two configuration files and shows a difference.",
+	if err := newCommand().Run(context.Background(), os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitTrouble)
+	}
+}
+
+func newCommand() *cli.Command {
+	return &cli.Command{
+		Name: "gendiff",
+		Usage: fmt.Sprintf("Compares configuration files (%s) and shows a difference.",
+			strings.Join(gendiff.Parsers(), ", ")),
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:    "format",
+				Sources: cli.EnvVars("GENDIFF_FORMAT"),
 				Aliases: []string{"f"},
-				Usage:   "output format (default: \"stylish\")",
+				Value:   "stylish",
+				Usage:   "output format: " + strings.Join(gendiff.Formatters(), ", "),
+				Validator: func(format string) error {
+					if !slices.Contains(gendiff.Formatters(), format) && !slices.Contains(gendiff.MatrixFormats(), format) {
+						return fmt.Errorf("unknown format %q", format)
+					}
+					return nil
+				},
+			},
+			&cli.StringSliceFlag{
+				Name:    "ignore",
+				Sources: cli.EnvVars("GENDIFF_IGNORE"),
+				Usage:   "dotted path to leave out of the diff, \"*\" matches any key (repeatable)",
+			},
+			&cli.StringSliceFlag{
+				Name:    "only",
+				Sources: cli.EnvVars("GENDIFF_ONLY"),
+				Usage:   "dotted path to limit the diff to, \"*\" matches any key (repeatable)",
+			},
+			&cli.StringMapFlag{
+				Name:    "comparator",
+				Sources: cli.EnvVars("GENDIFF_COMPARATOR"),
+				Usage:   "path=name comparison for a path: " + strings.Join(gendiff.Comparators(), ", ") + " (repeatable)",
+			},
+			&cli.BoolFlag{
+				Name:    "positions",
+				Sources: cli.EnvVars("GENDIFF_POSITIONS"),
+				Usage:   "show source file:line positions in plain and json output",
+			},
+			&cli.BoolFlag{
+				Name:    "redact",
+				Sources: cli.EnvVars("GENDIFF_REDACT"),
+				Value:   true,
+				Usage:   "mask passwords, tokens, keys and other secret-looking values (--redact=false to show them)",
+			},
+			&cli.StringSliceFlag{
+				Name:    "redact-path",
+				Sources: cli.EnvVars("GENDIFF_REDACT_PATH"),
+				Usage:   "dotted path whose value is always masked, \"*\" matches any key (repeatable)",
+			},
+			&cli.StringFlag{
+				Name:    "redact-key",
+				Sources: cli.EnvVars("GENDIFF_REDACT_KEY"),
+				Usage:   "key for the hash after masked values, so tags match across runs (random by default)",
+			},
+			&cli.BoolFlag{
+				Name:    "quiet",
+				Aliases: []string{"q"},
+				Usage:   "suppress output and report differences only through the exit status",
+			},
+			&cli.BoolFlag{
+				Name:  "stat",
+				Usage: "show only change counts per section (same as --format stat)",
+			},
+			&cli.BoolFlag{
+				Name:  "matrix",
+				Usage: "compare the files as a matrix of values per path (implied by more than two files)",
+			},
+			&cli.StringFlag{
+				Name:  "baseline",
+				Usage: "in matrix mode, mark values that differ from this file (a path or 1-based position)",
+			},
+			&cli.BoolFlag{
+				Name:  "watch",
+				Usage: "re-render the diff whenever either file changes, until interrupted",
+			},
+			&cli.BoolFlag{
+				Name:  "stream",
+				Usage: "diff large JSON files with sorted keys without loading them, writing " + strings.Join(gendiff.StreamFormats(), " or ") + " output as it goes",
+			},
+			&cli.StringFlag{
+				Name:  "array-key",
+				Usage: "with --stream, match array elements by this field instead of by index",
+			},
+			&cli.BoolFlag{
+				Name:  "tui",
+				Usage: "browse the diff as an interactive tree",
+			},
+			&cli.StringFlag{
+				Name:    "color",
+				Sources: cli.EnvVars("GENDIFF_COLOR"),
+				Value:   colorAuto,
+				Usage:   "colorize output: auto, always or never",
+			},
+			&cli.IntFlag{
+				Name:    "width",
+				Sources: cli.EnvVars("GENDIFF_WIDTH"),
+				Usage:   "line width for side-by-side output, 0 to detect the terminal width",
+			},
+			&cli.StringFlag{
+				Name:    "config",
+				Sources: cli.EnvVars("GENDIFF_CONFIG"),
+				Usage:   "config file, by default .gendiffrc or gendiff.yaml in the working directory or a parent",
+			},
+			&cli.StringFlag{
+				Name:    "profile",
+				Sources: cli.EnvVars("GENDIFF_PROFILE"),
+				Usage:   "named profile from the config file",
 			},
 			&cli.BoolFlag{
 				Name:    "help",
@@ -26,31 +139,105 @@ func main() {
 				Usage:   "show help",
 			},
 		},
+		Commands: []*cli.Command{
+			serveCommand(),
+			gitDriverCommand(),
+			installGitDriverCommand(),
+			{
+				Name:  "config",
+				Usage: "inspect the effective configuration",
+				Commands: []*cli.Command{
+					{
+						Name:  "show",
+						Usage: "print the effective settings and where each one comes from",
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							settings, err := loadSettings(cmd)
+							if err != nil {
+								return cli.Exit(fmt.Sprintf("Error: %v", err), exitTrouble)
+							}
+							out, err := settings.show()
+							if err != nil {
+								return cli.Exit(fmt.Sprintf("Error: %v", err), exitTrouble)
+							}
+							fmt.Fprint(cmd.Root().Writer, out)
+							return nil
+						},
+					},
+				},
+			},
+		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
-			if cmd.NArg() != 2 {
-				return cli.Exit("Error: Expected 2 file paths", 1)
+			matrix := cmd.NArg() > 2 || cmd.Bool("matrix") || cmd.String("baseline") != ""
+			if cmd.NArg() < 2 || cmd.NArg() > 2 && !matrix {
+				return cli.Exit("Error: Expected 2 file paths", exitTrouble)
+			}
+
+			settings, err := loadSettings(cmd)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("Error: %v", err), exitTrouble)
+			}
+			if matrix {
+				return runMatrix(cmd, settings)
 			}
 
 			filepath1 := cmd.Args().Get(0)
 			filepath2 := cmd.Args().Get(1)
-			format := cmd.String("format")
-			if format == "" {
-				format = "stylish"
+			format := settings.Format
+			if cmd.Bool("stat") {
+				format = "stat"
+			}
+
+			color, err := resolveColor(settings.Color, os.Stdout)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("Error: %v", err), exitTrouble)
+			}
+
+			opts := gendiff.Options{
+				Ignore:      settings.Ignore,
+				Only:        settings.Only,
+				Comparators: settings.Comparators,
+				Color:       color,
+				Width:       resolveWidth(settings.Width, os.Stdout),
+				Positions:   settings.Positions,
+				ShowSecrets: !settings.Redact,
+				RedactPaths: settings.RedactPaths,
+				RedactKey:   settings.RedactKey,
+				OldFile:     filepath1,
+				NewFile:     filepath2,
 			}
 
-			result, err := code.GenDiff(filepath1, filepath2, format)
+			if cmd.Bool("stream") {
+				return stream(cmd, filepath1, filepath2, format, opts)
+			}
+			if cmd.Bool("watch") {
+				return watch(ctx, filepath1, filepath2, format, opts, os.Stdout)
+			}
 
+			diff, err := gendiff.CompareFiles(filepath1, filepath2, opts)
 			if err != nil {
-				return cli.Exit(fmt.Sprintf("Error: %v", err), 1)
+				return cli.Exit(fmt.Sprintf("Error: %v", err), exitTrouble)
 			}
 
-			fmt.Println(result)
+			if cmd.Bool("tui") {
+				if err := browse(diff, os.Stdin, os.Stdout); err != nil {
+					return cli.Exit(fmt.Sprintf("Error: %v", err), exitTrouble)
+				}
+			} else if !cmd.Bool("quiet") {
+				if err := gendiff.RenderWithOptions(os.Stdout, diff, format, opts); err != nil {
+					return cli.Exit(fmt.Sprintf("Error: %v", err), exitTrouble)
+				}
+				fmt.Println()
+			}
+			if format == "github" {
+				if err := writeStepSummary(os.Getenv(githubStepSummaryEnv), diff); err != nil {
+					return cli.Exit(fmt.Sprintf("Error: %v", err), exitTrouble)
+				}
+			}
 
+			if diff.HasChanges() {
+				return cli.Exit("", exitDifferent)
+			}
 			return nil
 		},
 	}
-
-	if err := cmd.Run(context.Background(), os.Args); err != nil {
-		log.Fatal(err)
-	}
 }
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	gendiff "github.com/jobsboris27/go-project-244"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/urfave/cli/v3"
+)
+
+func runMatrix(cmd *cli.Command, settings *settings) error {
+	paths := cmd.Args().Slice()
+	baseline, err := resolveBaseline(cmd.String("baseline"), paths)
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("Error: %v", err), exitTrouble)
+	}
+
+	color, err := resolveColor(settings.Color, os.Stdout)
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("Error: %v", err), exitTrouble)
+	}
+	opts := gendiff.Options{
+		Ignore:      settings.Ignore,
+		Only:        settings.Only,
+		Comparators: settings.Comparators,
+		Color:       color,
+		ShowSecrets: !settings.Redact,
+		RedactPaths: settings.RedactPaths,
+		RedactKey:   settings.RedactKey,
+	}
+
+	matrix, err := gendiff.CompareMatrix(paths, baseline, opts)
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("Error: %v", err), exitTrouble)
+	}
+
+	if !cmd.Bool("quiet") {
+		if err := gendiff.RenderMatrix(os.Stdout, matrix, settings.Format, opts); err != nil {
+			return cli.Exit(fmt.Sprintf("Error: %v", err), exitTrouble)
+		}
+		fmt.Println()
+	}
+
+	if matrix.HasDifferences() {
+		return cli.Exit("", exitDifferent)
+	}
+	return nil
+}
+
+func resolveBaseline(baseline string, paths []string) (int, error) {
+	if baseline == "" {
+		return -1, nil
+	}
+	if i := slices.Index(paths, baseline); i >= 0 {
+		return i, nil
+	}
+	if n, err := strconv.Atoi(baseline); err == nil && n >= 1 && n <= len(paths) {
+		return n - 1, nil
+	}
+	return 0, fmt.Errorf("baseline %q is neither one of the compared files nor a position from 1 to %d", baseline, len(paths))
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	gendiff "github.com/jobsboris27/go-project-244"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/urfave/cli/v3"
+)
+
+const shutdownTimeout = 10 * time.Second
+
+func serveCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "serve",
+		Usage: "serve POST /diff as an HTTP JSON API",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "addr",
+				Value:   ":8080",
+				Sources: cli.EnvVars("GENDIFF_ADDR"),
+				Usage:   "address to listen on",
+			},
+			&cli.Int64Flag{
+				Name:    "max-body",
+				Value:   gendiff.DefaultMaxBodyBytes,
+				Sources: cli.EnvVars("GENDIFF_MAX_BODY"),
+				Usage:   "maximum request body size in bytes",
+			},
+			&cli.DurationFlag{
+				Name:    "timeout",
+				Value:   gendiff.DefaultTimeout,
+				Sources: cli.EnvVars("GENDIFF_TIMEOUT"),
+				Usage:   "maximum time to read, diff and answer one request",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			settings, err := loadSettings(cmd)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("Error: %v", err), exitTrouble)
+			}
+
+			handler := gendiff.NewServer(gendiff.ServerOptions{
+				MaxBodyBytes: cmd.Int64("max-body"),
+				Timeout:      cmd.Duration("timeout"),
+				Format:       settings.Format,
+				Defaults: gendiff.Options{
+					Ignore:      settings.Ignore,
+					Only:        settings.Only,
+					Comparators: settings.Comparators,
+					ShowSecrets: !settings.Redact,
+					RedactPaths: settings.RedactPaths,
+					RedactKey:   settings.RedactKey,
+					Positions:   settings.Positions,
+				},
+			})
+
+			timeout := cmd.Duration("timeout")
+			server := &http.Server{
+				Addr:              cmd.String("addr"),
+				Handler:           handler,
+				ReadHeaderTimeout: 10 * time.Second,
+				ReadTimeout:       timeout,
+				WriteTimeout:      timeout + 5*time.Second,
+				IdleTimeout:       time.Minute,
+			}
+
+			if err := serve(ctx, server, handler); err != nil {
+				return cli.Exit(fmt.Sprintf("Error: %v", err), exitTrouble)
+			}
+			return nil
+		},
+	}
+}
+
+func serve(ctx context.Context, server *http.Server, handler *gendiff.Server) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errs := make(chan error, 1)
+	go func() {
+		fmt.Fprintf(os.Stderr, "gendiff: listening on %s\n", server.Addr)
+		errs <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	handler.SetReady(false)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
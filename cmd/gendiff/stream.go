@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	gendiff "github.com/jobsboris27/go-project-244"
+	"io"
+	"os"
+
+	"github.com/urfave/cli/v3"
+)
+
+func stream(cmd *cli.Command, filepath1, filepath2, format string, opts gendiff.Options) error {
+	var out io.Writer = os.Stdout
+	if cmd.Bool("quiet") {
+		out = io.Discard
+	}
+
+	changed, err := gendiff.StreamFiles(out, filepath1, filepath2, format, gendiff.StreamOptions{
+		Options:  opts,
+		ArrayKey: cmd.String("array-key"),
+	})
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("Error: %v", err), exitTrouble)
+	}
+	if !cmd.Bool("quiet") {
+		fmt.Println()
+	}
+
+	if changed {
+		return cli.Exit("", exitDifferent)
+	}
+	return nil
+}
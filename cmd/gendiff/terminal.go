@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"golang.org/x/term"
+)
+
+const (
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
+)
+
+func resolveColor(mode string, out *os.File) (bool, error) {
+	switch mode {
+	case colorAlways:
+		return true, nil
+	case colorNever:
+		return false, nil
+	case colorAuto, "":
+		if os.Getenv("NO_COLOR") != "" {
+			return false, nil
+		}
+		if force := os.Getenv("FORCE_COLOR"); force != "" && force != "0" {
+			return true, nil
+		}
+		return isTerminal(out), nil
+	default:
+		return false, fmt.Errorf("invalid --color value %q: expected auto, always or never", mode)
+	}
+}
+
+func resolveWidth(width int, out *os.File) int {
+	if width > 0 {
+		return width
+	}
+	if isTerminal(out) {
+		if columns, _, err := term.GetSize(int(out.Fd())); err == nil && columns > 0 {
+			return columns
+		}
+	}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return 0
+}
+
+func isTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	gendiff "github.com/jobsboris27/go-project-244"
+	"github.com/jobsboris27/go-project-244/internal/tui"
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+const (
+	enterAltScreen = "\033[?1049h\033[?25l"
+	leaveAltScreen = "\033[?25h\033[?1049l"
+	cursorHome     = "\033[H"
+)
+
+func browse(diff gendiff.Diff, in, out *os.File) error {
+	if !isTerminal(in) || !isTerminal(out) {
+		return errors.New("--tui needs an interactive terminal")
+	}
+
+	state, err := term.MakeRaw(int(in.Fd()))
+	if err != nil {
+		return fmt.Errorf("switching terminal to raw mode: %w", err)
+	}
+	defer term.Restore(int(in.Fd()), state)
+
+	fmt.Fprint(out, enterAltScreen)
+	defer fmt.Fprint(out, leaveAltScreen)
+
+	model := tui.New(diff)
+	reader := bufio.NewReader(in)
+
+	for {
+		if width, height, err := term.GetSize(int(out.Fd())); err == nil {
+			model.SetSize(width, height)
+		}
+		fmt.Fprint(out, cursorHome+model.View())
+
+		key, err := tui.ReadKey(reader)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		action := model.Update(key)
+		if action.Copy != "" {
+			fmt.Fprint(out, tui.OSC52(action.Copy))
+		}
+		if action.Quit {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	gendiff "github.com/jobsboris27/go-project-244"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+)
+
+const (
+	pollInterval  = 200 * time.Millisecond
+	debounceDelay = 300 * time.Millisecond
+	clearScreen   = "\033[H\033[2J"
+)
+
+type fileState struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+func (s fileState) equal(other fileState) bool {
+	return s.exists == other.exists && s.size == other.size && s.modTime.Equal(other.modTime)
+}
+
+type snapshot [2]fileState
+
+func (s snapshot) equal(other snapshot) bool {
+	return s[0].equal(other[0]) && s[1].equal(other[1])
+}
+
+func takeSnapshot(path1, path2 string) snapshot {
+	return snapshot{statFile(path1), statFile(path2)}
+}
+
+func statFile(path string) fileState {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{exists: true, size: info.Size(), modTime: info.ModTime()}
+}
+
+func watch(ctx context.Context, path1, path2, format string, opts gendiff.Options, out *os.File) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	clear := isTerminal(out)
+	last := takeSnapshot(path1, path2)
+	rendered := renderWatched(out, clear, path1, path2, format, opts, last)
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	var changedAt time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			current := takeSnapshot(path1, path2)
+			if !current.equal(last) {
+				last = current
+				changedAt = now
+				rendered = false
+				continue
+			}
+			if !rendered && now.Sub(changedAt) >= debounceDelay {
+				rendered = renderWatched(out, clear, path1, path2, format, opts, current)
+			}
+		}
+	}
+}
+
+// renderWatched reports false when a file changed while it was being read, so
+// the caller renders again once writes settle instead of showing a diff of a
+// half-written file.
+func renderWatched(out io.Writer, clear bool, path1, path2, format string, opts gendiff.Options, before snapshot) bool {
+	diff, err := gendiff.CompareFiles(path1, path2, opts)
+	if !takeSnapshot(path1, path2).equal(before) {
+		return false
+	}
+
+	if clear {
+		fmt.Fprint(out, clearScreen)
+	} else {
+		fmt.Fprintln(out)
+	}
+	fmt.Fprintf(out, "%s  %s vs %s\n\n", time.Now().Format("15:04:05"), path1, path2)
+
+	if err == nil {
+		err = gendiff.RenderWithOptions(out, diff, format, opts)
+	}
+	if err != nil {
+		fmt.Fprintf(out, "Error: %v", err)
+	}
+	fmt.Fprintln(out)
+	return true
+}
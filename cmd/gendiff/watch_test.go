@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	gendiff "github.com/jobsboris27/go-project-244"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchRerendersOnChange(t *testing.T) {
+	dir := t.TempDir()
+	path1 := filepath.Join(dir, "old.json")
+	path2 := filepath.Join(dir, "new.json")
+	require.NoError(t, os.WriteFile(path1, []byte(`{"replicas": 1}`), 0o644))
+	require.NoError(t, os.WriteFile(path2, []byte(`{"replicas": 1}`), 0o644))
+
+	out, err := os.Create(filepath.Join(dir, "out.txt"))
+	require.NoError(t, err)
+	defer out.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- watch(ctx, path1, path2, "plain", gendiff.Options{}, out) }()
+
+	output := func() string {
+		data, _ := os.ReadFile(out.Name())
+		return string(data)
+	}
+	assert.Eventually(t, func() bool { return strings.Contains(output(), "old.json vs") }, 5*time.Second, 20*time.Millisecond)
+
+	// Same size as before, so only the modification time tells the change apart.
+	require.NoError(t, os.WriteFile(path2, []byte(`{"replicas": 2}`), 0o644))
+	later := time.Now().Add(time.Second)
+	require.NoError(t, os.Chtimes(path2, later, later))
+
+	assert.Eventually(t, func() bool {
+		return strings.Contains(output(), "Property 'replicas' was updated. From 1 to 2")
+	}, 5*time.Second, 20*time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
@@ -1,22 +1,329 @@
-package code
+// Package gendiff compares configuration documents (JSON, YAML) and renders
+// the difference in one of several output formats.
+package gendiff
 
 import (
-	"code/internal/formatters"
-	parsers "code/internal/parsers"
+	_ "embed"
 	"fmt"
+	"github.com/jobsboris27/go-project-244/internal/formatters"
+	"github.com/jobsboris27/go-project-244/internal/models"
+	parsers "github.com/jobsboris27/go-project-244/internal/parsers"
+	"github.com/jobsboris27/go-project-244/internal/redact"
+	"io"
+	"strings"
 )
 
+//go:embed schema/diff.schema.json
+var diffSchema []byte
+
+// Node is a single entry of a Diff: a key with its status and values.
+type Node = models.DiffNode
+
+// Diff is the structural difference between two documents.
+type Diff = models.Diff
+
+// Position is the file, line and column a key was read from.
+type Position = models.Position
+
+// Matrix compares the values of every path across several documents; see
+// CompareMatrix.
+type Matrix = models.Matrix
+
+// Stat holds change counts for a Diff and each of its subtrees.
+type Stat = models.DiffStat
+
+// Formatter renders a Diff in a custom output format; see RegisterFormatter.
+type Formatter = formatters.Formatter
+
+// FormatterFunc adapts a function to the Formatter interface.
+type FormatterFunc = formatters.FormatterFunc
+
+// Parser decodes an input format; see RegisterParser.
+type Parser = parsers.Parser
+
+// Locator is optionally implemented by a Parser to report the source position
+// of every key, indexed by dotted path such as "common.setting6" or "list.[0]".
+type Locator = parsers.Locator
+
+// RenderOptions is the rendering subset of Options passed to a Formatter.
+type RenderOptions = formatters.Options
+
+// Options controls how documents are compared and rendered.
+type Options struct {
+	// Ignore lists dotted paths, such as "common.setting6", left out of the diff
+	// with their subtrees; a "*" segment matches any key.
+	Ignore []string
+	// Only limits the diff to the given dotted paths and their subtrees; a "*"
+	// segment matches any key.
+	Only []string
+	// Comparators maps dotted paths to a named comparison used instead of exact
+	// equality: "numeric", "case-insensitive" or "trim-space". Values the
+	// comparator considers equal are reported as unchanged.
+	Comparators map[string]string
+	// Color enables ANSI colors in the stylish, plain and side-by-side formats.
+	Color bool
+	// Width is the line width of the side-by-side format.
+	Width int
+	// Positions adds source positions to the plain and json formats.
+	Positions bool
+	// ShowSecrets turns off masking of values that look like secrets. By
+	// default keys named like passwords, tokens or secrets, AWS access keys,
+	// JWTs and PEM blocks are masked: they read "***" followed by a short hash
+	// that differs when the value changes.
+	ShowSecrets bool
+	// RedactPaths lists dotted paths that are always masked, even with
+	// ShowSecrets; a "*" segment matches any key, as in "services.*.dsn".
+	RedactPaths []string
+	// RedactKey keys the hash shown after masked values. With a key, the same
+	// secret gets the same tag in every run; without one, a random key is drawn
+	// for each comparison and tags cannot be compared across runs.
+	RedactKey string
+	// OldFile and NewFile name the compared files for formats that annotate
+	// files when nodes carry no positions. GenDiff fills them in.
+	OldFile string
+	NewFile string
+}
+
+// Compare diffs two Go values. They may be decoded documents, such as the
+// result of unmarshalling JSON or YAML into an any, or arbitrary structs, maps
+// and slices; struct fields are named after their json or yaml tags.
+// Non-object values are compared under a "root" key.
+func Compare(a, b any, opts Options) (Diff, error) {
+	tree1, err := parsers.ConvertValueToTree(a)
+	if err != nil {
+		return nil, fmt.Errorf("converting first value: %w", err)
+	}
+
+	tree2, err := parsers.ConvertValueToTree(b)
+	if err != nil {
+		return nil, fmt.Errorf("converting second value: %w", err)
+	}
+
+	return opts.finish(parsers.GetDiff(tree1, tree2))
+}
+
+// CompareFiles parses two files and diffs them. The parser is picked by file
+// extension, falling back to sniffing the content for unknown extensions.
+// Nodes carry the source positions of their keys when the parser reports them.
+func CompareFiles(path1, path2 string, opts Options) (Diff, error) {
+	tree1, err := parsers.LoadTree(path1)
+	if err != nil {
+		return nil, fmt.Errorf("parsing file %s: %w", path1, err)
+	}
+
+	tree2, err := parsers.LoadTree(path2)
+	if err != nil {
+		return nil, fmt.Errorf("parsing file %s: %w", path2, err)
+	}
+
+	return opts.finish(parsers.GetDiff(tree1, tree2))
+}
+
+// CompareMatrix parses any number of files and lists, for every leaf path, the
+// value in each file. A row differs when its values are not all equal. With a
+// baseline index (pass -1 for none), each cell is also marked when it differs
+// from the baseline file. Ignore, Only, Comparators and redaction apply as in
+// CompareFiles.
+func CompareMatrix(paths []string, baseline int, opts Options) (*Matrix, error) {
+	if baseline >= len(paths) {
+		return nil, fmt.Errorf("baseline %d is out of range for %d files", baseline, len(paths))
+	}
+
+	trees := make([]*models.TreeNode, len(paths))
+	for i, path := range paths {
+		tree, err := parsers.LoadTree(path)
+		if err != nil {
+			return nil, fmt.Errorf("parsing file %s: %w", path, err)
+		}
+		trees[i] = tree
+	}
+
+	if err := parsers.ValidateComparators(opts.Comparators); err != nil {
+		return nil, err
+	}
+	matrix := parsers.BuildMatrix(paths, trees, parsers.MatrixOptions{
+		Baseline:    baseline,
+		Ignore:      opts.Ignore,
+		Only:        opts.Only,
+		Comparators: opts.Comparators,
+	})
+	return opts.redactor().RedactMatrix(matrix), nil
+}
+
+// RenderMatrix writes matrix to w as a table, json or html.
+func RenderMatrix(w io.Writer, matrix *Matrix, format string, opts Options) error {
+	return formatters.RenderMatrix(w, matrix, format, opts.renderOptions())
+}
+
+// MatrixFormats returns the formats RenderMatrix accepts.
+func MatrixFormats() []string {
+	return formatters.MatrixFormats()
+}
+
+// Document is an in-memory input for CompareDocuments.
+type Document struct {
+	// Name is used for positions and, through its extension, to pick a parser.
+	Name string
+	// Type names a registered parser, such as "yaml"; it overrides Name.
+	Type string
+	// MIMEType picks a parser when neither Type nor the extension of Name do.
+	MIMEType string
+	Content  []byte
+}
+
+// CompareDocuments parses two in-memory documents and diffs them. Without a
+// Type, the parser is picked by the extension of Name, then by MIMEType, then
+// by sniffing the content.
+func CompareDocuments(a, b Document, opts Options) (Diff, error) {
+	tree1, err := a.tree()
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", a.Name, err)
+	}
+
+	tree2, err := b.tree()
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", b.Name, err)
+	}
+
+	return opts.finish(parsers.GetDiff(tree1, tree2))
+}
+
+// Render writes diff to w in the given format.
+func Render(w io.Writer, diff Diff, format string) error {
+	return RenderWithOptions(w, diff, format, Options{})
+}
+
+// RenderWithOptions writes diff to w in the given format using opts.
+// It fails if no formatter is registered under format.
+func RenderWithOptions(w io.Writer, diff Diff, format string, opts Options) error {
+	return formatters.Render(w, diff, format, opts.renderOptions())
+}
+
+// RegisterFormatter makes a formatter available under name to Render, GenDiff
+// and the --format flag of the CLI. Names must be unique.
+func RegisterFormatter(name string, formatter Formatter) error {
+	return formatters.Register(name, formatter)
+}
+
+// UnregisterFormatter removes the formatter registered under name and reports
+// whether there was one.
+func UnregisterFormatter(name string) bool {
+	return formatters.Unregister(name)
+}
+
+// RegisterParser makes an input format available to CompareFiles and the CLI.
+// Names must be unique and an extension can belong to a single parser.
+func RegisterParser(name string, parser Parser) error {
+	return parsers.Register(name, parser)
+}
+
+// UnregisterParser removes the parser registered under name and reports
+// whether there was one.
+func UnregisterParser(name string) bool {
+	return parsers.Unregister(name)
+}
+
+// Parsers returns the sorted names of all registered parsers.
+func Parsers() []string {
+	return parsers.Names()
+}
+
+// Extensions returns the file extensions handled by the registered parsers.
+func Extensions() []string {
+	return parsers.Extensions()
+}
+
+// Formatters returns the sorted names of all registered formatters.
+func Formatters() []string {
+	return formatters.Names()
+}
+
+// Comparators returns the names accepted as values of Options.Comparators.
+func Comparators() []string {
+	return parsers.Comparators()
+}
+
+// DiffSchema returns the JSON Schema of the document produced by the json and
+// yaml formats. Its "version" property identifies the format revision.
+func DiffSchema() []byte {
+	return append([]byte(nil), diffSchema...)
+}
+
+// Stats counts added, removed, modified and unchanged keys in diff.
+func Stats(diff Diff) *Stat {
+	return formatters.ComputeStats(diff)
+}
+
+// GenDiff compares two files and returns the difference rendered in format.
 func GenDiff(path1, path2, format string) (string, error) {
-	data1, err := parsers.ParseByExtension(path1)
+	return GenDiffWithOptions(path1, path2, format, Options{})
+}
+
+// GenDiffWithOptions is like GenDiff but honours opts.
+func GenDiffWithOptions(path1, path2, format string, opts Options) (string, error) {
+	diff, err := CompareFiles(path1, path2, opts)
+	if err != nil {
+		return "", err
+	}
+	if opts.OldFile == "" && opts.NewFile == "" {
+		opts.OldFile, opts.NewFile = path1, path2
+	}
+	return formatters.RenderWithOptions(diff, format, opts.renderOptions())
+}
+
+// GenDiffValues compares two Go values and returns the difference rendered in format.
+func GenDiffValues(a, b any, format string) (string, error) {
+	diff, err := Compare(a, b, Options{})
+	if err != nil {
+		return "", err
+	}
+	return formatters.RenderWithFormat(diff, format)
+}
+
+// GenStats compares two files and returns their change counts.
+func GenStats(path1, path2 string) (*Stat, error) {
+	diff, err := CompareFiles(path1, path2, Options{})
+	if err != nil {
+		return nil, err
+	}
+	return Stats(diff), nil
+}
+
+func (d Document) tree() (*models.TreeNode, error) {
+	if d.Type != "" {
+		parser, ok := parsers.ForName(d.Type)
+		if !ok {
+			return nil, fmt.Errorf("unknown document type %q (available: %s)", d.Type, strings.Join(parsers.Names(), ", "))
+		}
+		return parsers.ParseTree(d.Content, d.Name, parser)
+	}
+
+	parser, err := parsers.ResolveParser(d.Name, d.MIMEType, d.Content)
 	if err != nil {
-		return "", fmt.Errorf("parsing file %s: %w", path1, err)
+		return nil, err
 	}
+	return parsers.ParseTree(d.Content, d.Name, parser)
+}
 
-	data2, err := parsers.ParseByExtension(path2)
+func (o Options) finish(diff Diff) (Diff, error) {
+	diff, err := parsers.ApplyComparators(diff, o.Comparators)
 	if err != nil {
-		return "", fmt.Errorf("parsing file %s: %w", path1, err)
+		return nil, err
 	}
+	diff = parsers.OnlyPaths(parsers.IgnorePaths(diff, o.Ignore), o.Only)
+	return o.redactor().Redact(diff), nil
+}
 
-	diff := parsers.GetDiff(parsers.СonvertMapToTree(data1), parsers.СonvertMapToTree(data2))
-	return formatters.RenderWithFormat(diff, format), nil
+func (o Options) redactor() *redact.Redactor {
+	return redact.New(redact.Rules{Detect: !o.ShowSecrets, Paths: o.RedactPaths, Key: []byte(o.RedactKey)})
+}
+
+func (o Options) renderOptions() formatters.Options {
+	return formatters.Options{
+		Color:     o.Color,
+		Width:     o.Width,
+		Positions: o.Positions,
+		OldFile:   o.OldFile,
+		NewFile:   o.NewFile,
+	}
 }
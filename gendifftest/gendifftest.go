@@ -0,0 +1,147 @@
+// Package gendifftest compares documents produced in tests against golden
+// files, reporting mismatches as a structural diff.
+//
+// To rewrite golden files from the current output, define an -update flag in
+// the test package and run its tests with it:
+//
+//	var update = flag.Bool("update", false, "rewrite golden files")
+//
+//	go test ./internal/render -update
+//
+// gendifftest looks the flag up by name when asserting and defines none
+// itself, so it never conflicts with a flag the test package declares.
+package gendifftest
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	gendiff "github.com/jobsboris27/go-project-244"
+	parsers "github.com/jobsboris27/go-project-244/internal/parsers"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func updating() bool {
+	update := flag.Lookup("update")
+	if update == nil {
+		return false
+	}
+	value, _ := strconv.ParseBool(update.Value.String())
+	return value
+}
+
+// AssertEqualDocs reports a test error unless got matches the golden document
+// at goldenPath. got may be the document text as a string or []byte, parsed
+// like the golden file, or a Go value compared through gendiff.Compare.
+// opts.Ignore, opts.Only and opts.Comparators relax the comparison.
+//
+// With -update, the golden file is rewritten from got instead: text is written
+// as is and Go values are encoded as JSON or YAML by the golden file extension.
+func AssertEqualDocs(t testing.TB, got any, goldenPath string, opts gendiff.Options) bool {
+	t.Helper()
+
+	if updating() {
+		if err := writeGolden(goldenPath, got); err != nil {
+			t.Errorf("updating golden file %s: %v", goldenPath, err)
+			return false
+		}
+		return true
+	}
+
+	golden, err := os.ReadFile(goldenPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("golden file %s does not exist; run the test with -update to create it", goldenPath)
+		return false
+	}
+	if err != nil {
+		t.Errorf("reading golden file %s: %v", goldenPath, err)
+		return false
+	}
+
+	opts.Color = false
+	diff, err := compare(golden, got, goldenPath, opts)
+	if err != nil {
+		t.Errorf("comparing with golden file %s: %v", goldenPath, err)
+		return false
+	}
+	if !diff.HasChanges() {
+		return true
+	}
+
+	var report strings.Builder
+	if err := gendiff.RenderWithOptions(&report, diff, "stylish", opts); err != nil {
+		t.Errorf("rendering diff against %s: %v", goldenPath, err)
+		return false
+	}
+	t.Errorf("document differs from golden file %s (- golden, + got):\n%s\nrun the test with -update to accept the change",
+		goldenPath, report.String())
+	return false
+}
+
+func compare(golden []byte, got any, goldenPath string, opts gendiff.Options) (gendiff.Diff, error) {
+	want := gendiff.Document{Name: goldenPath, Content: golden}
+
+	switch v := got.(type) {
+	case []byte:
+		return gendiff.CompareDocuments(want, gendiff.Document{Name: goldenPath, Content: v}, opts)
+	case string:
+		return gendiff.CompareDocuments(want, gendiff.Document{Name: goldenPath, Content: []byte(v)}, opts)
+	}
+
+	parser, err := parsers.ResolveParser(goldenPath, "", golden)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := parser.Parse(strings.NewReader(string(golden)))
+	if err != nil {
+		return nil, err
+	}
+	return gendiff.Compare(raw, got, opts)
+}
+
+func writeGolden(path string, got any) error {
+	var data []byte
+	switch v := got.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		value, err := parsers.NormalizeValue(got)
+		if err != nil {
+			return err
+		}
+		data, err = encode(path, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func encode(path string, value any) ([]byte, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case parsers.JSON_EXT:
+		data, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case parsers.YAML_EXT, parsers.YAML_EXT_SHORT:
+		return yaml.Marshal(value)
+	default:
+		return nil, fmt.Errorf("cannot encode a Go value for %s; use a .json or .yaml golden file", path)
+	}
+}
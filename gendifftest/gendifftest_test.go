@@ -0,0 +1,114 @@
+package gendifftest
+
+import (
+	"flag"
+	"fmt"
+	gendiff "github.com/jobsboris27/go-project-244"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The golden files in testdata are fixtures for these tests, so -update must
+// not rewrite them. The flag itself is defined in update_test.go.
+func TestMain(m *testing.M) {
+	flag.Parse()
+	_ = flag.Set("update", "false")
+	os.Exit(m.Run())
+}
+
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+type image struct {
+	Tag        string `yaml:"tag"`
+	PullPolicy string `yaml:"pullPolicy"`
+}
+
+type config struct {
+	Name        string `yaml:"name"`
+	Replicas    int    `yaml:"replicas"`
+	Image       image  `yaml:"image"`
+	GeneratedAt string `yaml:"generatedAt"`
+}
+
+func TestAssertEqualDocsMatches(t *testing.T) {
+	text := "replicas: 3\nname: api\nimage: {tag: '1.4.2', pullPolicy: IfNotPresent}\ngeneratedAt: '2026-01-01T00:00:00Z'\n"
+	assert.True(t, AssertEqualDocs(t, text, "testdata/config.yaml", gendiff.Options{}))
+
+	value := config{Name: "api", Replicas: 3, Image: image{Tag: "1.4.2", PullPolicy: "IfNotPresent"}, GeneratedAt: "today"}
+	assert.True(t, AssertEqualDocs(t, value, "testdata/config.yaml", gendiff.Options{Ignore: []string{"generatedAt"}}))
+}
+
+func TestAssertEqualDocsReportsDiff(t *testing.T) {
+	rec := &recorder{TB: t}
+	value := config{Name: "api", Replicas: 4, Image: image{Tag: "1.4.2", PullPolicy: "Always"}, GeneratedAt: "2026-01-01T00:00:00Z"}
+
+	assert.False(t, AssertEqualDocs(rec, value, "testdata/config.yaml", gendiff.Options{}))
+	assert.Len(t, rec.errors, 1)
+	assert.Contains(t, rec.errors[0], "testdata/config.yaml")
+	assert.Contains(t, rec.errors[0], "      - pullPolicy: IfNotPresent\n      + pullPolicy: Always")
+	assert.Contains(t, rec.errors[0], "  - replicas: 3\n  + replicas: 4")
+	assert.Contains(t, rec.errors[0], "-update")
+}
+
+func TestAssertEqualDocsComparators(t *testing.T) {
+	rec := &recorder{TB: t}
+	text := `{"name": "API", "replicas": "3.0", "image": {"tag": "1.4.2", "pullPolicy": "IfNotPresent"}, "generatedAt": "2026-01-01T00:00:00Z"}`
+
+	assert.False(t, AssertEqualDocs(rec, text, "testdata/config.yaml", gendiff.Options{}))
+	assert.True(t, AssertEqualDocs(t, text, "testdata/config.yaml", gendiff.Options{Comparators: map[string]string{
+		"name":     "case-insensitive",
+		"replicas": "numeric",
+	}}))
+}
+
+func TestAssertEqualDocsMissingGolden(t *testing.T) {
+	rec := &recorder{TB: t}
+	assert.False(t, AssertEqualDocs(rec, "a: 1", "testdata/missing.yaml", gendiff.Options{}))
+	assert.Contains(t, rec.errors[0], "does not exist")
+}
+
+func TestAssertEqualDocsUpdate(t *testing.T) {
+	setUpdate(t, true)
+	t.Cleanup(func() { setUpdate(t, false) })
+
+	dir := t.TempDir()
+	value := config{Name: "api", Replicas: 2, Image: image{Tag: "2.0.0"}}
+
+	jsonPath := filepath.Join(dir, "golden", "config.json")
+	yamlPath := filepath.Join(dir, "config.yaml")
+	textPath := filepath.Join(dir, "raw.yaml")
+	assert.True(t, AssertEqualDocs(t, value, jsonPath, gendiff.Options{}))
+	assert.True(t, AssertEqualDocs(t, value, yamlPath, gendiff.Options{}))
+	assert.True(t, AssertEqualDocs(t, []byte("# kept as is\na: 1\n"), textPath, gendiff.Options{}))
+
+	data, err := os.ReadFile(textPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# kept as is\na: 1\n", string(data))
+
+	setUpdate(t, false)
+	assert.True(t, AssertEqualDocs(t, value, jsonPath, gendiff.Options{}))
+	assert.True(t, AssertEqualDocs(t, value, yamlPath, gendiff.Options{}))
+
+	setUpdate(t, true)
+	rec := &recorder{TB: t}
+	assert.False(t, AssertEqualDocs(rec, value, filepath.Join(dir, "config.toml"), gendiff.Options{}))
+	assert.Contains(t, rec.errors[0], "use a .json or .yaml golden file")
+}
+
+func setUpdate(t *testing.T, value bool) {
+	t.Helper()
+	assert.NoError(t, flag.Set("update", strconv.FormatBool(value)))
+}
@@ -0,0 +1,30 @@
+package gendifftest_test
+
+import (
+	"flag"
+	gendiff "github.com/jobsboris27/go-project-244"
+	"github.com/jobsboris27/go-project-244/gendifftest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// A test package importing gendifftest declares its own -update flag, as
+// the package documentation asks.
+var update = flag.Bool("update", false, "rewrite golden files with the current output")
+
+func TestAssertEqualDocsFollowsPackageUpdateFlag(t *testing.T) {
+	t.Cleanup(func() { *update = false })
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	*update = true
+	assert.True(t, gendifftest.AssertEqualDocs(t, map[string]any{"replicas": 2}, path, gendiff.Options{}))
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "replicas: 2\n", string(data))
+
+	*update = false
+	assert.True(t, gendifftest.AssertEqualDocs(t, "replicas: 2", path, gendiff.Options{}))
+}
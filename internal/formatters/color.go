@@ -0,0 +1,60 @@
+package formatters
+
+const (
+	ansiReset  = "\033[0m"
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+	ansiDim    = "\033[2m"
+)
+
+type Options struct {
+	Color     bool
+	Width     int
+	Positions bool
+	OldFile   string
+	NewFile   string
+}
+
+type palette struct {
+	added     string
+	removed   string
+	modified  string
+	unchanged string
+}
+
+var (
+	noColors   = palette{}
+	ansiColors = palette{
+		added:     ansiGreen,
+		removed:   ansiRed,
+		modified:  ansiYellow,
+		unchanged: ansiDim,
+	}
+)
+
+func paletteFor(opts Options) palette {
+	if opts.Color {
+		return ansiColors
+	}
+	return noColors
+}
+
+func (p palette) paint(status, text string) string {
+	var color string
+	switch status {
+	case ADDED:
+		color = p.added
+	case REMOVED:
+		color = p.removed
+	case MODIFIED:
+		color = p.modified
+	case UNCHANGED:
+		color = p.unchanged
+	}
+
+	if color == "" {
+		return text
+	}
+	return color + text + ansiReset
+}
@@ -1,7 +1,12 @@
 package formatters
 
 import (
-	models "code/internal/models"
+	"fmt"
+	models "github.com/jobsboris27/go-project-244/internal/models"
+	"io"
+	"sort"
+	"strings"
+	"sync"
 )
 
 const (
@@ -10,15 +15,146 @@ const (
 	JSON    = "json"
 )
 
-func RenderWithFormat(diffNodes []*models.DiffNode, format string) string {
-	switch format {
-	case PLAIN:
-		return RenderPlain(diffNodes, "")
-	case JSON:
-		return RenderJSON(diffNodes)
-	case STYLISH:
-		return RenderStylish(diffNodes, 0)
-	default:
-		return RenderStylish(diffNodes, 0)
+type Formatter interface {
+	Render(w io.Writer, diffNodes []*models.DiffNode, opts Options) error
+}
+
+type FormatterFunc func(w io.Writer, diffNodes []*models.DiffNode, opts Options) error
+
+func (f FormatterFunc) Render(w io.Writer, diffNodes []*models.DiffNode, opts Options) error {
+	return f(w, diffNodes, opts)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Formatter)
+)
+
+func init() {
+	builtins := map[string]func([]*models.DiffNode, Options) string{
+		STYLISH:      RenderStylishWithOptions,
+		PLAIN:        RenderPlainWithOptions,
+		MARKDOWN:     ignoreOptions(RenderMarkdown),
+		SIDE_BY_SIDE: RenderSideBySide,
+		STAT:         ignoreOptions(RenderStat),
+	}
+	fallible := map[string]func([]*models.DiffNode, Options) (string, error){
+		HTML:  func(diffNodes []*models.DiffNode, _ Options) (string, error) { return RenderHTML(diffNodes) },
+		JSON:  RenderJSONWithOptions,
+		SARIF: RenderSARIF,
+		JUNIT: RenderJUnit,
+		YAML:  RenderYAML,
+	}
+
+	for name, render := range builtins {
+		if err := Register(name, stringFormatter(render)); err != nil {
+			panic(err)
+		}
+	}
+	for name, render := range fallible {
+		if err := Register(name, fallibleFormatter(render)); err != nil {
+			panic(err)
+		}
+	}
+	if err := Register(GITHUB, FormatterFunc(RenderGitHub)); err != nil {
+		panic(err)
+	}
+}
+
+func Register(name string, formatter Formatter) error {
+	if name == "" {
+		return fmt.Errorf("formatter name must not be empty")
+	}
+	if formatter == nil {
+		return fmt.Errorf("formatter %q is nil", name)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := registry[name]; exists {
+		return fmt.Errorf("formatter %q is already registered", name)
+	}
+	registry[name] = formatter
+	return nil
+}
+
+func Unregister(name string) bool {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	_, exists := registry[name]
+	delete(registry, name)
+	return exists
+}
+
+func Lookup(name string) (Formatter, error) {
+	if name == "" {
+		name = STYLISH
+	}
+
+	registryMu.RLock()
+	formatter, ok := registry[name]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown format %q (available: %s)", name, strings.Join(Names(), ", "))
+	}
+	return formatter, nil
+}
+
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func Render(w io.Writer, diffNodes []*models.DiffNode, format string, opts Options) error {
+	formatter, err := Lookup(format)
+	if err != nil {
+		return err
+	}
+	return formatter.Render(w, diffNodes, opts)
+}
+
+func RenderWithFormat(diffNodes []*models.DiffNode, format string) (string, error) {
+	return RenderWithOptions(diffNodes, format, Options{})
+}
+
+func RenderWithOptions(diffNodes []*models.DiffNode, format string, opts Options) (string, error) {
+	var result strings.Builder
+	if err := Render(&result, diffNodes, format, opts); err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
+
+func stringFormatter(render func([]*models.DiffNode, Options) string) Formatter {
+	return FormatterFunc(func(w io.Writer, diffNodes []*models.DiffNode, opts Options) error {
+		_, err := io.WriteString(w, render(diffNodes, opts))
+		return err
+	})
+}
+
+func fallibleFormatter(render func([]*models.DiffNode, Options) (string, error)) Formatter {
+	return FormatterFunc(func(w io.Writer, diffNodes []*models.DiffNode, opts Options) error {
+		result, err := render(diffNodes, opts)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, result)
+		return err
+	})
+}
+
+func ignoreOptions(render func([]*models.DiffNode) string) func([]*models.DiffNode, Options) string {
+	return func(diffNodes []*models.DiffNode, _ Options) string {
+		return render(diffNodes)
 	}
 }
@@ -0,0 +1,69 @@
+package formatters
+
+import (
+	"fmt"
+	models "github.com/jobsboris27/go-project-244/internal/models"
+	"io"
+	"strings"
+)
+
+const (
+	GITHUB = "github"
+
+	githubTitle = "Config drift"
+)
+
+func RenderGitHub(w io.Writer, diffNodes []*models.DiffNode, opts Options) error {
+	annotations := strings.TrimSuffix(renderGitHubAnnotations(diffNodes, "", opts), "\n")
+	_, err := io.WriteString(w, annotations)
+	return err
+}
+
+func renderGitHubAnnotations(diffNodes []*models.DiffNode, path string, opts Options) string {
+	var result strings.Builder
+
+	for _, node := range diffNodes {
+		currentPath := buildPath(path, node.Key)
+
+		switch node.Status {
+		case ADDED, REMOVED, MODIFIED:
+			result.WriteString(fmt.Sprintf("::warning %s::%s\n",
+				githubProperties(node, opts), escapeGitHubData(plainMessage(node, currentPath))))
+		case NESTED:
+			result.WriteString(renderGitHubAnnotations(node.Children, currentPath, opts))
+		}
+	}
+
+	return result.String()
+}
+
+func githubProperties(node *models.DiffNode, opts Options) string {
+	var properties []string
+
+	if position := node.Position(); position != nil && position.File != "" {
+		properties = append(properties,
+			"file="+escapeGitHubProperty(position.File),
+			fmt.Sprintf("line=%d", position.Line),
+			fmt.Sprintf("col=%d", position.Column))
+	} else if file := fallbackFile(node, opts); file != "" {
+		properties = append(properties, "file="+escapeGitHubProperty(file))
+	}
+
+	properties = append(properties, "title="+escapeGitHubProperty(githubTitle))
+	return strings.Join(properties, ",")
+}
+
+func fallbackFile(node *models.DiffNode, opts Options) string {
+	if node.Status == REMOVED || opts.NewFile == "" {
+		return opts.OldFile
+	}
+	return opts.NewFile
+}
+
+func escapeGitHubData(text string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(text)
+}
+
+func escapeGitHubProperty(text string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(text)
+}
@@ -0,0 +1,73 @@
+package formatters
+
+import (
+	"embed"
+	"fmt"
+	models "github.com/jobsboris27/go-project-244/internal/models"
+	"html/template"
+	"strings"
+)
+
+const HTML = "html"
+
+//go:embed templates/*.html
+var templatesFS embed.FS
+
+var htmlReport = template.Must(template.ParseFS(templatesFS, "templates/report.html"))
+
+type htmlRow struct {
+	Key      string
+	Path     string
+	Status   string
+	OldValue string
+	NewValue string
+	Children []htmlRow
+}
+
+type htmlPage struct {
+	Counts *models.DiffStat
+	Rows   []htmlRow
+}
+
+func RenderHTML(diffNodes []*models.DiffNode) (string, error) {
+	page := htmlPage{
+		Counts: ComputeStats(diffNodes),
+		Rows:   buildHTMLRows(diffNodes, ""),
+	}
+
+	var result strings.Builder
+	if err := htmlReport.Execute(&result, page); err != nil {
+		return "", fmt.Errorf("render html: %w", err)
+	}
+	return result.String(), nil
+}
+
+func buildHTMLRows(diffNodes []*models.DiffNode, path string) []htmlRow {
+	rows := make([]htmlRow, 0, len(diffNodes))
+
+	for _, node := range diffNodes {
+		row := htmlRow{
+			Key:    node.Key,
+			Path:   buildPath(path, node.Key),
+			Status: node.Status,
+		}
+
+		switch node.Status {
+		case ADDED:
+			row.NewValue = formatValue(node.NewValue, 0)
+		case REMOVED:
+			row.OldValue = formatValue(node.OldValue, 0)
+		case MODIFIED:
+			row.OldValue = formatValue(node.OldValue, 0)
+			row.NewValue = formatValue(node.NewValue, 0)
+		case UNCHANGED:
+			row.OldValue = formatValue(node.OldValue, 0)
+		case NESTED:
+			row.Children = buildHTMLRows(node.Children, row.Path)
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows
+}
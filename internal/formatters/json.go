@@ -1,29 +1,34 @@
 package formatters
 
 import (
-	models "code/internal/models"
 	"encoding/json"
 	"fmt"
+	models "github.com/jobsboris27/go-project-244/internal/models"
 )
 
-func RenderJSON(diffNodes []*models.DiffNode) string {
-	jsonData := convertToJSONFormat(diffNodes)
+const DIFF_SCHEMA_VERSION = "1.0"
 
-	resultMap := map[string]interface{}{
-		"diff": jsonData,
-	}
-
-	result, err := json.MarshalIndent(resultMap, "", "  ")
+func RenderJSON(diffNodes []*models.DiffNode) (string, error) {
+	return RenderJSONWithOptions(diffNodes, Options{})
+}
 
+func RenderJSONWithOptions(diffNodes []*models.DiffNode, opts Options) (string, error) {
+	result, err := json.MarshalIndent(diffDocument(diffNodes, opts), "", "  ")
 	if err != nil {
-		fmt.Println("render json %w", err)
-		return ""
+		return "", fmt.Errorf("render json: %w", err)
+	}
+	return string(result), nil
+}
+
+func diffDocument(diffNodes []*models.DiffNode, opts Options) map[string]interface{} {
+	return map[string]interface{}{
+		"version": DIFF_SCHEMA_VERSION,
+		"diff":    convertToJSONFormat(diffNodes, opts.Positions),
 	}
-	return string(result)
 }
 
-func convertToJSONFormat(diffNodes []*models.DiffNode) []map[string]interface{} {
-	var result []map[string]interface{}
+func convertToJSONFormat(diffNodes []*models.DiffNode, positions bool) []map[string]interface{} {
+	result := []map[string]interface{}{}
 
 	for _, node := range diffNodes {
 		switch node.Status {
@@ -60,10 +65,23 @@ func convertToJSONFormat(diffNodes []*models.DiffNode) []map[string]interface{}
 			result = append(result, map[string]interface{}{
 				"key":      node.Key,
 				"type":     NESTED,
-				"children": convertToJSONFormat(node.Children),
+				"children": convertToJSONFormat(node.Children, positions),
 			})
 		}
+
+		if positions {
+			addJSONPositions(result[len(result)-1], node)
+		}
 	}
 
 	return result
 }
+
+func addJSONPositions(entry map[string]interface{}, node *models.DiffNode) {
+	if node.OldPosition != nil {
+		entry["oldPosition"] = node.OldPosition
+	}
+	if node.NewPosition != nil {
+		entry["newPosition"] = node.NewPosition
+	}
+}
@@ -0,0 +1,140 @@
+package formatters
+
+import (
+	"encoding/xml"
+	"fmt"
+	models "github.com/jobsboris27/go-project-244/internal/models"
+)
+
+const (
+	JUNIT = "junit"
+
+	junitDefaultSuite = "gendiff"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",cdata"`
+}
+
+func RenderJUnit(diffNodes []*models.DiffNode, opts Options) (string, error) {
+	report := junitTestSuites{Name: junitReportName(opts), Time: "0"}
+
+	defaultSuite := junitTestSuite{Name: junitSuiteName(opts), Time: "0"}
+	for _, node := range diffNodes {
+		if node.Status == NESTED {
+			suite := junitTestSuite{Name: node.Key, Time: "0"}
+			addJUnitCases(&suite, node.Children, node.Key)
+			report.Suites = append(report.Suites, suite)
+			continue
+		}
+		addJUnitCases(&defaultSuite, []*models.DiffNode{node}, "")
+	}
+	if len(defaultSuite.Cases) > 0 {
+		report.Suites = append([]junitTestSuite{defaultSuite}, report.Suites...)
+	}
+
+	for _, suite := range report.Suites {
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+	}
+
+	result, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("render junit: %w", err)
+	}
+	return xml.Header + string(result), nil
+}
+
+func addJUnitCases(suite *junitTestSuite, diffNodes []*models.DiffNode, path string) {
+	for _, node := range diffNodes {
+		currentPath := buildPath(path, node.Key)
+
+		if node.Status == NESTED {
+			addJUnitCases(suite, node.Children, currentPath)
+			continue
+		}
+
+		testCase := junitTestCase{Name: currentPath, Classname: suite.Name, Time: "0"}
+		position := node.Position()
+		if position != nil {
+			testCase.File = position.File
+		}
+
+		if node.Status != UNCHANGED {
+			testCase.Failure = &junitFailure{
+				Message: plainMessage(node, currentPath),
+				Type:    node.Status,
+				Text:    junitLocation(position) + junitFailureText(node),
+			}
+			suite.Failures++
+		}
+
+		suite.Cases = append(suite.Cases, testCase)
+		suite.Tests++
+	}
+}
+
+func junitLocation(position *models.Position) string {
+	if position == nil {
+		return ""
+	}
+	if position.File == "" {
+		return fmt.Sprintf("at line %d\n", position.Line)
+	}
+	return fmt.Sprintf("at %s:%d\n", position.File, position.Line)
+}
+
+func junitFailureText(node *models.DiffNode) string {
+	switch node.Status {
+	case ADDED:
+		return "old: (absent)\nnew: " + formatValue(node.NewValue, 0)
+	case REMOVED:
+		return "old: " + formatValue(node.OldValue, 0) + "\nnew: (absent)"
+	default:
+		return "old: " + formatValue(node.OldValue, 0) + "\nnew: " + formatValue(node.NewValue, 0)
+	}
+}
+
+func junitReportName(opts Options) string {
+	if opts.OldFile == "" || opts.NewFile == "" {
+		return junitDefaultSuite
+	}
+	return fmt.Sprintf("%s: %s vs %s", junitDefaultSuite, opts.OldFile, opts.NewFile)
+}
+
+func junitSuiteName(opts Options) string {
+	if opts.NewFile != "" {
+		return opts.NewFile
+	}
+	return junitDefaultSuite
+}
@@ -0,0 +1,171 @@
+package formatters
+
+import (
+	"fmt"
+	models "github.com/jobsboris27/go-project-244/internal/models"
+	"strings"
+)
+
+const (
+	MARKDOWN = "markdown"
+
+	markdownMaxRows      = 100
+	markdownMaxBytes     = 60000
+	markdownMaxCellWidth = 80
+)
+
+type markdownRow struct {
+	path     string
+	change   string
+	oldValue interface{}
+	newValue interface{}
+	hasOld   bool
+	hasNew   bool
+}
+
+func RenderMarkdown(diffNodes []*models.DiffNode) string {
+	rows := collectMarkdownRows(diffNodes, "")
+	if len(rows) == 0 {
+		return "No changes."
+	}
+
+	var table, details strings.Builder
+	table.WriteString(markdownSummary(rows))
+	table.WriteString("| Path | Change | Old | New |\n")
+	table.WriteString("| --- | --- | --- | --- |\n")
+
+	rendered := 0
+	for _, row := range rows {
+		if rendered == markdownMaxRows {
+			break
+		}
+
+		oldCell := markdownCell(row.path, "old", row.oldValue, row.hasOld)
+		newCell := markdownCell(row.path, "new", row.newValue, row.hasNew)
+		line := fmt.Sprintf("| %s | %s | %s | %s |\n",
+			markdownCode(row.path), row.change, oldCell.text, newCell.text)
+
+		if table.Len()+details.Len()+len(line)+len(oldCell.details)+len(newCell.details) > markdownMaxBytes {
+			break
+		}
+
+		table.WriteString(line)
+		details.WriteString(oldCell.details)
+		details.WriteString(newCell.details)
+		rendered++
+	}
+
+	var result strings.Builder
+	result.WriteString(table.String())
+	if details.Len() > 0 {
+		result.WriteString("\n")
+		result.WriteString(details.String())
+	}
+	if rest := len(rows) - rendered; rest > 0 {
+		result.WriteString(fmt.Sprintf("\n_… and %d more changes_\n", rest))
+	}
+
+	return strings.TrimSpace(result.String())
+}
+
+func collectMarkdownRows(diffNodes []*models.DiffNode, path string) []markdownRow {
+	var rows []markdownRow
+
+	for _, node := range diffNodes {
+		currentPath := buildPath(path, node.Key)
+
+		switch node.Status {
+		case ADDED:
+			rows = append(rows, markdownRow{path: currentPath, change: ADDED, newValue: node.NewValue, hasNew: true})
+		case REMOVED:
+			rows = append(rows, markdownRow{path: currentPath, change: REMOVED, oldValue: node.OldValue, hasOld: true})
+		case MODIFIED:
+			rows = append(rows, markdownRow{
+				path:     currentPath,
+				change:   UPDATED,
+				oldValue: node.OldValue,
+				newValue: node.NewValue,
+				hasOld:   true,
+				hasNew:   true,
+			})
+		case NESTED:
+			rows = append(rows, collectMarkdownRows(node.Children, currentPath)...)
+		}
+	}
+
+	return rows
+}
+
+func markdownSummary(rows []markdownRow) string {
+	counts := make(map[string]int)
+	for _, row := range rows {
+		counts[row.change]++
+	}
+
+	return fmt.Sprintf("**%d changes:** %d added, %d removed, %d updated\n\n",
+		len(rows), counts[ADDED], counts[REMOVED], counts[UPDATED])
+}
+
+type markdownValue struct {
+	text    string
+	details string
+}
+
+func markdownCell(path, side string, value interface{}, present bool) markdownValue {
+	if !present {
+		return markdownValue{text: ""}
+	}
+
+	text := formatPlainValue(value)
+	if text != "[complex value]" && !strings.Contains(text, "\n") && len([]rune(text)) <= markdownMaxCellWidth {
+		return markdownValue{text: markdownCode(text)}
+	}
+
+	full := formatValue(value, 0)
+	fence := markdownFence(full)
+	block := fmt.Sprintf("<details><summary><code>%s</code> (%s)</summary>\n\n%s\n%s\n%s\n\n</details>\n",
+		escapeMarkdownHTML(path), side, fence, full, fence)
+
+	return markdownValue{text: "_see details_", details: block}
+}
+
+func markdownCode(text string) string {
+	text = strings.NewReplacer("\r", "", "\n", " ", "|", "\\|").Replace(text)
+
+	delimiter := strings.Repeat("`", longestBacktickRun(text)+1)
+	if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+		text = " " + text + " "
+	}
+	return delimiter + text + delimiter
+}
+
+func escapeMarkdownHTML(text string) string {
+	replacer := strings.NewReplacer(
+		"&", "&amp;",
+		"<", "&lt;",
+		">", "&gt;",
+	)
+	return replacer.Replace(text)
+}
+
+func markdownFence(text string) string {
+	if longest := longestBacktickRun(text); longest >= 3 {
+		return strings.Repeat("`", longest+1)
+	}
+	return "```"
+}
+
+func longestBacktickRun(text string) int {
+	longest, current := 0, 0
+	for _, r := range text {
+		if r != '`' {
+			current = 0
+			continue
+		}
+		current++
+		if current > longest {
+			longest = current
+		}
+	}
+	return longest
+}
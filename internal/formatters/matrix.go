@@ -0,0 +1,245 @@
+package formatters
+
+import (
+	"encoding/json"
+	"fmt"
+	models "github.com/jobsboris27/go-project-244/internal/models"
+	"html/template"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	TABLE = "table"
+
+	matrixMaxColumnWidth = 40
+	matrixMissing        = "—"
+	matrixDiffers        = "≠"
+)
+
+var matrixFormats = []string{TABLE, JSON, HTML}
+
+var htmlMatrix = template.Must(template.ParseFS(templatesFS, "templates/matrix.html"))
+
+func MatrixFormats() []string {
+	return append([]string(nil), matrixFormats...)
+}
+
+func RenderMatrix(w io.Writer, matrix *models.Matrix, format string, opts Options) error {
+	var result string
+	var err error
+	switch format {
+	case TABLE, STYLISH, "":
+		result = RenderMatrixTable(matrix, opts)
+	case JSON:
+		result, err = RenderMatrixJSON(matrix)
+	case HTML:
+		result, err = RenderMatrixHTML(matrix)
+	default:
+		return fmt.Errorf("format %q does not support more than two files (available: %s)",
+			format, strings.Join(matrixFormats, ", "))
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, result)
+	return err
+}
+
+func RenderMatrixTable(matrix *models.Matrix, opts Options) string {
+	colors := paletteFor(opts)
+
+	header := append([]string{"path"}, matrixHeaders(matrix)...)
+	table := [][]string{header}
+	for _, row := range matrix.Rows {
+		line := []string{row.Path}
+		for _, cell := range row.Cells {
+			line = append(line, matrixCellText(cell))
+		}
+		table = append(table, line)
+	}
+
+	widths := make([]int, len(header))
+	for _, line := range table {
+		for i, text := range line {
+			widths[i] = min(max(widths[i], utf8.RuneCountInString(text)), matrixMaxColumnWidth)
+		}
+	}
+
+	var result strings.Builder
+	for i, line := range table {
+		marker := "  "
+		var row *models.MatrixRow
+		if i > 0 {
+			row = matrix.Rows[i-1]
+			if row.Differs {
+				marker = matrixDiffers + " "
+			}
+		}
+
+		columns := make([]string, len(line))
+		for j, text := range line {
+			column := fitColumn(text, widths[j])
+			if j == len(line)-1 {
+				column = strings.TrimRight(column, " ")
+			}
+			columns[j] = colors.paint(matrixCellStatus(matrix, row, j), column)
+		}
+
+		result.WriteString(strings.TrimRight(marker+strings.Join(columns, "  "), " "))
+		if i < len(table)-1 {
+			result.WriteString("\n")
+		}
+	}
+
+	return result.String()
+}
+
+func matrixCellStatus(matrix *models.Matrix, row *models.MatrixRow, column int) string {
+	if row == nil {
+		return ""
+	}
+	if !row.Differs {
+		return UNCHANGED
+	}
+	if column == 0 || !matrix.HasBaseline() {
+		return MODIFIED
+	}
+
+	cell := row.Cells[column-1]
+	switch {
+	case !cell.Differs:
+		return ""
+	case !cell.Present:
+		return REMOVED
+	default:
+		return MODIFIED
+	}
+}
+
+func matrixHeaders(matrix *models.Matrix) []string {
+	headers := make([]string, len(matrix.Files))
+	for i, file := range matrix.Files {
+		headers[i] = file
+		if matrix.HasBaseline() && i == matrix.Baseline {
+			headers[i] += " (baseline)"
+		}
+	}
+	return headers
+}
+
+func matrixCellText(cell models.MatrixCell) string {
+	if !cell.Present {
+		return matrixMissing
+	}
+	switch value := cell.Value.(type) {
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprintf("%v", value)
+		}
+		return string(data)
+	default:
+		return strings.ReplaceAll(formatValue(value, 0), "\n", "⏎")
+	}
+}
+
+type jsonMatrix struct {
+	Version  string          `json:"version"`
+	Files    []string        `json:"files"`
+	Baseline *string         `json:"baseline,omitempty"`
+	Rows     []jsonMatrixRow `json:"rows"`
+}
+
+type jsonMatrixRow struct {
+	Path    string           `json:"path"`
+	Differs bool             `json:"differs"`
+	Values  []jsonMatrixCell `json:"values"`
+}
+
+type jsonMatrixCell struct {
+	Present bool        `json:"present"`
+	Value   interface{} `json:"value"`
+	Differs bool        `json:"differs"`
+}
+
+func RenderMatrixJSON(matrix *models.Matrix) (string, error) {
+	document := jsonMatrix{
+		Version: DIFF_SCHEMA_VERSION,
+		Files:   matrix.Files,
+		Rows:    []jsonMatrixRow{},
+	}
+	if matrix.HasBaseline() {
+		document.Baseline = &matrix.Files[matrix.Baseline]
+	}
+
+	for _, row := range matrix.Rows {
+		jsonRow := jsonMatrixRow{Path: row.Path, Differs: row.Differs}
+		for _, cell := range row.Cells {
+			jsonRow.Values = append(jsonRow.Values, jsonMatrixCell{
+				Present: cell.Present,
+				Value:   cell.Value,
+				Differs: cell.Differs,
+			})
+		}
+		document.Rows = append(document.Rows, jsonRow)
+	}
+
+	result, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("render matrix json: %w", err)
+	}
+	return string(result), nil
+}
+
+type htmlMatrixPage struct {
+	Headers  []string
+	Baseline int
+	Rows     []htmlMatrixRow
+	Total    int
+	Differs  int
+}
+
+type htmlMatrixRow struct {
+	Path    string
+	Differs bool
+	Cells   []htmlMatrixCell
+}
+
+type htmlMatrixCell struct {
+	Text    string
+	Present bool
+	Differs bool
+}
+
+func RenderMatrixHTML(matrix *models.Matrix) (string, error) {
+	page := htmlMatrixPage{
+		Headers:  matrixHeaders(matrix),
+		Baseline: -1,
+		Total:    len(matrix.Rows),
+		Differs:  matrix.DifferingRows(),
+	}
+	if matrix.HasBaseline() {
+		page.Baseline = matrix.Baseline
+	}
+
+	for _, row := range matrix.Rows {
+		htmlRow := htmlMatrixRow{Path: row.Path, Differs: row.Differs}
+		for _, cell := range row.Cells {
+			htmlRow.Cells = append(htmlRow.Cells, htmlMatrixCell{
+				Text:    matrixCellText(cell),
+				Present: cell.Present,
+				Differs: row.Differs && (cell.Differs || !matrix.HasBaseline()),
+			})
+		}
+		page.Rows = append(page.Rows, htmlRow)
+	}
+
+	var result strings.Builder
+	if err := htmlMatrix.Execute(&result, page); err != nil {
+		return "", fmt.Errorf("render matrix html: %w", err)
+	}
+	return result.String(), nil
+}
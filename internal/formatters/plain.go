@@ -1,12 +1,20 @@
 package formatters
 
 import (
-	models "code/internal/models"
 	"fmt"
+	models "github.com/jobsboris27/go-project-244/internal/models"
 	"strings"
 )
 
 func RenderPlain(diffNodes []*models.DiffNode, path string) string {
+	return renderPlain(diffNodes, path, noColors, false)
+}
+
+func RenderPlainWithOptions(diffNodes []*models.DiffNode, opts Options) string {
+	return renderPlain(diffNodes, "", paletteFor(opts), opts.Positions)
+}
+
+func renderPlain(diffNodes []*models.DiffNode, path string, colors palette, positions bool) string {
 	var result strings.Builder
 
 	if len(diffNodes) == 0 {
@@ -17,19 +25,12 @@ func RenderPlain(diffNodes []*models.DiffNode, path string) string {
 		currentPath := buildPath(path, node.Key)
 
 		switch node.Status {
-		case ADDED:
-			result.WriteString(fmt.Sprintf("Property '%s' was added with value: %s\n",
-				currentPath, formatPlainValue(node.NewValue)))
-
-		case REMOVED:
-			result.WriteString(fmt.Sprintf("Property '%s' was removed\n", currentPath))
-
-		case MODIFIED:
-			result.WriteString(fmt.Sprintf("Property '%s' was updated. From %s to %s\n",
-				currentPath, formatPlainValue(node.OldValue), formatPlainValue(node.NewValue)))
+		case ADDED, REMOVED, MODIFIED:
+			line := plainPrefix(node, positions) + plainMessage(node, currentPath)
+			result.WriteString(colors.paint(node.Status, line) + "\n")
 
 		case NESTED:
-			nestedResult := RenderPlain(node.Children, currentPath)
+			nestedResult := renderPlain(node.Children, currentPath, colors, positions)
 			if nestedResult != "" {
 				result.WriteString(nestedResult)
 				result.WriteString("\n")
@@ -40,6 +41,31 @@ func RenderPlain(diffNodes []*models.DiffNode, path string) string {
 	return strings.TrimSpace(result.String())
 }
 
+func plainMessage(node *models.DiffNode, path string) string {
+	switch node.Status {
+	case ADDED:
+		return fmt.Sprintf("Property '%s' was added with value: %s", path, formatPlainValue(node.NewValue))
+	case REMOVED:
+		return fmt.Sprintf("Property '%s' was removed", path)
+	case MODIFIED:
+		return fmt.Sprintf("Property '%s' was updated. From %s to %s",
+			path, formatPlainValue(node.OldValue), formatPlainValue(node.NewValue))
+	default:
+		return ""
+	}
+}
+
+func plainPrefix(node *models.DiffNode, positions bool) string {
+	position := node.Position()
+	if !positions || position == nil {
+		return ""
+	}
+	if position.File == "" {
+		return fmt.Sprintf("%d: ", position.Line)
+	}
+	return fmt.Sprintf("%s:%d: ", position.File, position.Line)
+}
+
 func buildPath(path, key string) string {
 	if path == "" {
 		return key
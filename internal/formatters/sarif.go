@@ -0,0 +1,159 @@
+package formatters
+
+import (
+	"encoding/json"
+	"fmt"
+	models "github.com/jobsboris27/go-project-244/internal/models"
+	"net/url"
+	"path/filepath"
+)
+
+const (
+	SARIF = "sarif"
+
+	TYPE_CHANGED = "type-changed"
+
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	toolName     = "gendiff"
+	toolURI      = "https://github.com/jobsboris27/go-project-244"
+)
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	Name             string       `json:"name"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+	DefaultConfig    sarifConfig  `json:"defaultConfiguration"`
+}
+
+type sarifConfig struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+var sarifRules = []sarifRule{
+	{ID: ADDED, Name: "KeyAdded", ShortDescription: sarifMessage{"A key was added."}, DefaultConfig: sarifConfig{"note"}},
+	{ID: REMOVED, Name: "KeyRemoved", ShortDescription: sarifMessage{"A key was removed."}, DefaultConfig: sarifConfig{"warning"}},
+	{ID: MODIFIED, Name: "ValueModified", ShortDescription: sarifMessage{"A value was changed."}, DefaultConfig: sarifConfig{"warning"}},
+	{ID: TYPE_CHANGED, Name: "TypeChanged", ShortDescription: sarifMessage{"A value changed its type."}, DefaultConfig: sarifConfig{"error"}},
+}
+
+func RenderSARIF(diffNodes []*models.DiffNode, opts Options) (string, error) {
+	log := map[string]interface{}{
+		"$schema": sarifSchema,
+		"version": sarifVersion,
+		"runs": []map[string]interface{}{
+			{
+				"tool": map[string]interface{}{
+					"driver": map[string]interface{}{
+						"name":           toolName,
+						"informationUri": toolURI,
+						"rules":          sarifRules,
+					},
+				},
+				"results": collectSARIFResults(diffNodes, "", opts),
+			},
+		},
+	}
+
+	result, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("render sarif: %w", err)
+	}
+	return string(result), nil
+}
+
+func collectSARIFResults(diffNodes []*models.DiffNode, path string, opts Options) []sarifResult {
+	results := []sarifResult{}
+
+	for _, node := range diffNodes {
+		currentPath := buildPath(path, node.Key)
+
+		switch node.Status {
+		case ADDED, REMOVED, MODIFIED:
+			ruleIndex := sarifRuleIndex(node)
+			results = append(results, sarifResult{
+				RuleID:    sarifRules[ruleIndex].ID,
+				RuleIndex: ruleIndex,
+				Level:     sarifRules[ruleIndex].DefaultConfig.Level,
+				Message:   sarifMessage{Text: plainMessage(node, currentPath)},
+				Locations: []sarifLocation{sarifNodeLocation(node, currentPath, opts)},
+			})
+		case NESTED:
+			results = append(results, collectSARIFResults(node.Children, currentPath, opts)...)
+		}
+	}
+
+	return results
+}
+
+func sarifRuleIndex(node *models.DiffNode) int {
+	switch {
+	case node.Status == ADDED:
+		return 0
+	case node.Status == REMOVED:
+		return 1
+	case models.ValueKind(node.OldValue) != models.ValueKind(node.NewValue):
+		return 3
+	default:
+		return 2
+	}
+}
+
+func sarifNodeLocation(node *models.DiffNode, path string, opts Options) sarifLocation {
+	location := sarifLocation{
+		LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: path, Kind: "member"}},
+	}
+
+	if position := node.Position(); position != nil && position.File != "" {
+		location.PhysicalLocation = &sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: fileURI(position.File)},
+			Region:           &sarifRegion{StartLine: position.Line, StartColumn: position.Column},
+		}
+	} else if file := fallbackFile(node, opts); file != "" {
+		location.PhysicalLocation = &sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: fileURI(file)},
+		}
+	}
+
+	return location
+}
+
+func fileURI(path string) string {
+	return (&url.URL{Path: filepath.ToSlash(path)}).String()
+}
@@ -0,0 +1,122 @@
+package formatters
+
+import (
+	"fmt"
+	models "github.com/jobsboris27/go-project-244/internal/models"
+	"strings"
+)
+
+const (
+	SIDE_BY_SIDE = "side-by-side"
+
+	DefaultWidth = 130
+	minWidth     = 20
+	gutterWidth  = 3
+)
+
+type sideBySideRow struct {
+	left   string
+	right  string
+	status string
+}
+
+func RenderSideBySide(diffNodes []*models.DiffNode, opts Options) string {
+	width := opts.Width
+	if width <= 0 {
+		width = DefaultWidth
+	}
+	if width < minWidth {
+		width = minWidth
+	}
+	columnWidth := (width - gutterWidth) / 2
+
+	rows := []sideBySideRow{{left: "{", right: "{", status: NESTED}}
+	rows = append(rows, buildSideBySideRows(diffNodes, 0)...)
+	rows = append(rows, sideBySideRow{left: "}", right: "}", status: NESTED})
+
+	colors := paletteFor(opts)
+	var result strings.Builder
+	for _, row := range rows {
+		line := fmt.Sprintf("%s %s %s",
+			fitColumn(row.left, columnWidth), gutterMarker(row.status), fitColumn(row.right, columnWidth))
+		result.WriteString(colors.paint(row.status, strings.TrimRight(line, " ")))
+		result.WriteString("\n")
+	}
+
+	return strings.TrimSuffix(result.String(), "\n")
+}
+
+func buildSideBySideRows(diffNodes []*models.DiffNode, depth int) []sideBySideRow {
+	var rows []sideBySideRow
+	indent := strings.Repeat(" ", depth*IndentSize+IndentSize)
+
+	for _, node := range diffNodes {
+		switch node.Status {
+		case UNCHANGED:
+			lines := sideBySideLines(indent, node.Key, node.OldValue, depth)
+			rows = append(rows, pairLines(lines, lines, UNCHANGED)...)
+
+		case ADDED:
+			rows = append(rows, pairLines(nil, sideBySideLines(indent, node.Key, node.NewValue, depth), ADDED)...)
+
+		case REMOVED:
+			rows = append(rows, pairLines(sideBySideLines(indent, node.Key, node.OldValue, depth), nil, REMOVED)...)
+
+		case MODIFIED:
+			oldLines := sideBySideLines(indent, node.Key, node.OldValue, depth)
+			newLines := sideBySideLines(indent, node.Key, node.NewValue, depth)
+			rows = append(rows, pairLines(oldLines, newLines, MODIFIED)...)
+
+		case NESTED:
+			open := fmt.Sprintf("%s%s: {", indent, node.Key)
+			rows = append(rows, sideBySideRow{left: open, right: open, status: NESTED})
+			rows = append(rows, buildSideBySideRows(node.Children, depth+1)...)
+			rows = append(rows, sideBySideRow{left: indent + "}", right: indent + "}", status: NESTED})
+		}
+	}
+
+	return rows
+}
+
+func sideBySideLines(indent, key string, value interface{}, depth int) []string {
+	text := fmt.Sprintf("%s%s: %s", indent, key, formatValue(value, depth+1))
+	return strings.Split(text, "\n")
+}
+
+func pairLines(left, right []string, status string) []sideBySideRow {
+	count := max(len(left), len(right))
+	rows := make([]sideBySideRow, count)
+
+	for i := range rows {
+		rows[i].status = status
+		if i < len(left) {
+			rows[i].left = left[i]
+		}
+		if i < len(right) {
+			rows[i].right = right[i]
+		}
+	}
+
+	return rows
+}
+
+func gutterMarker(status string) string {
+	switch status {
+	case ADDED:
+		return ">"
+	case REMOVED:
+		return "<"
+	case MODIFIED:
+		return "|"
+	default:
+		return " "
+	}
+}
+
+func fitColumn(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) > width {
+		return string(runes[:width-1]) + "…"
+	}
+	return text + strings.Repeat(" ", width-len(runes))
+}
@@ -0,0 +1,110 @@
+package formatters
+
+import (
+	"fmt"
+	models "github.com/jobsboris27/go-project-244/internal/models"
+	"sort"
+	"strings"
+)
+
+const (
+	STAT = "stat"
+
+	statBarWidth = 40
+)
+
+func ComputeStats(diffNodes []*models.DiffNode) *models.DiffStat {
+	return computeStats("", diffNodes)
+}
+
+func computeStats(key string, diffNodes []*models.DiffNode) *models.DiffStat {
+	stat := &models.DiffStat{Key: key}
+
+	for _, node := range diffNodes {
+		child := &models.DiffStat{Key: node.Key}
+
+		switch node.Status {
+		case ADDED:
+			child.Added = 1
+		case REMOVED:
+			child.Removed = 1
+		case MODIFIED:
+			child.Modified = 1
+		case UNCHANGED:
+			child.Unchanged = 1
+		case NESTED:
+			child = computeStats(node.Key, node.Children)
+		}
+
+		stat.Added += child.Added
+		stat.Removed += child.Removed
+		stat.Modified += child.Modified
+		stat.Unchanged += child.Unchanged
+		stat.Children = append(stat.Children, child)
+	}
+
+	return stat
+}
+
+type statLine struct {
+	label string
+	stat  *models.DiffStat
+}
+
+func RenderStat(diffNodes []*models.DiffNode) string {
+	stats := ComputeStats(diffNodes)
+	lines := collectStatLines(stats.Children, 0)
+
+	labelWidth, countWidth, maxChanged := 0, 0, 0
+	for _, line := range lines {
+		labelWidth = max(labelWidth, len(line.label))
+		countWidth = max(countWidth, len(fmt.Sprint(line.stat.Changed())))
+		maxChanged = max(maxChanged, line.stat.Changed())
+	}
+
+	var result strings.Builder
+	for _, line := range lines {
+		result.WriteString(fmt.Sprintf(" %-*s | %*d %s\n",
+			labelWidth, line.label, countWidth, line.stat.Changed(), statBar(line.stat, maxChanged)))
+	}
+
+	result.WriteString(fmt.Sprintf(" %d keys changed: %d added, %d removed, %d modified, %d unchanged",
+		stats.Changed(), stats.Added, stats.Removed, stats.Modified, stats.Unchanged))
+
+	return result.String()
+}
+
+func collectStatLines(stats []*models.DiffStat, depth int) []statLine {
+	var changed []*models.DiffStat
+	for _, stat := range stats {
+		if stat.Changed() > 0 && (depth == 0 || len(stat.Children) > 0) {
+			changed = append(changed, stat)
+		}
+	}
+	sort.SliceStable(changed, func(i, j int) bool {
+		return changed[i].Changed() > changed[j].Changed()
+	})
+
+	var lines []statLine
+	for _, stat := range changed {
+		lines = append(lines, statLine{label: strings.Repeat("  ", depth) + stat.Key, stat: stat})
+		lines = append(lines, collectStatLines(stat.Children, depth+1)...)
+	}
+	return lines
+}
+
+func statBar(stat *models.DiffStat, maxChanged int) string {
+	added, removed, modified := stat.Added, stat.Removed, stat.Modified
+
+	if maxChanged > statBarWidth {
+		scale := func(n int) int {
+			if n == 0 {
+				return 0
+			}
+			return max(1, n*statBarWidth/maxChanged)
+		}
+		added, removed, modified = scale(added), scale(removed), scale(modified)
+	}
+
+	return strings.Repeat("+", added) + strings.Repeat("~", modified) + strings.Repeat("-", removed)
+}
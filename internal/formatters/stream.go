@@ -0,0 +1,119 @@
+package formatters
+
+import (
+	"bufio"
+	"fmt"
+	models "github.com/jobsboris27/go-project-244/internal/models"
+	"io"
+	"strings"
+)
+
+type StreamWriter interface {
+	models.NodeWriter
+	Close() error
+}
+
+var streamFormats = []string{STYLISH, PLAIN}
+
+func StreamFormats() []string {
+	return append([]string(nil), streamFormats...)
+}
+
+func NewStreamWriter(w io.Writer, format string, opts Options) (StreamWriter, error) {
+	switch format {
+	case STYLISH, "":
+		return &stylishStream{out: bufio.NewWriter(w), colors: paletteFor(opts)}, nil
+	case PLAIN:
+		return &plainStream{out: bufio.NewWriter(w), colors: paletteFor(opts), positions: opts.Positions}, nil
+	default:
+		return nil, fmt.Errorf("format %q cannot be streamed (available: %s)",
+			format, strings.Join(streamFormats, ", "))
+	}
+}
+
+type stylishStream struct {
+	out     *bufio.Writer
+	colors  palette
+	started bool
+}
+
+func (s *stylishStream) EnterNested(parents []string, key string) error {
+	if err := s.start(); err != nil {
+		return err
+	}
+	indent := strings.Repeat(" ", len(parents)*IndentSize+IndentSize)
+	_, err := fmt.Fprintf(s.out, "%s%s: {\n", indent, key)
+	return err
+}
+
+func (s *stylishStream) WriteNode(parents []string, node *models.DiffNode) error {
+	if err := s.start(); err != nil {
+		return err
+	}
+	_, err := s.out.WriteString(stylishNode(node, len(parents), s.colors))
+	return err
+}
+
+func (s *stylishStream) LeaveNested(parents []string, key string) error {
+	indent := strings.Repeat(" ", len(parents)*IndentSize+IndentSize)
+	_, err := fmt.Fprintf(s.out, "%s}\n", indent)
+	return err
+}
+
+func (s *stylishStream) Close() error {
+	if err := s.start(); err != nil {
+		return err
+	}
+	if _, err := s.out.WriteString("}"); err != nil {
+		return err
+	}
+	return s.out.Flush()
+}
+
+func (s *stylishStream) start() error {
+	if s.started {
+		return nil
+	}
+	s.started = true
+	_, err := s.out.WriteString("{\n")
+	return err
+}
+
+type plainStream struct {
+	out       *bufio.Writer
+	colors    palette
+	positions bool
+	wrote     bool
+}
+
+func (p *plainStream) EnterNested(parents []string, key string) error {
+	return nil
+}
+
+func (p *plainStream) WriteNode(parents []string, node *models.DiffNode) error {
+	switch node.Status {
+	case ADDED, REMOVED, MODIFIED:
+	default:
+		return nil
+	}
+
+	if p.wrote {
+		if _, err := p.out.WriteString("\n"); err != nil {
+			return err
+		}
+	}
+	p.wrote = true
+
+	path := buildPath(strings.Join(parents, "."), node.Key)
+	line := plainPrefix(node, p.positions) + plainMessage(node, path)
+	_, err := p.out.WriteString(p.colors.paint(node.Status, line))
+	return err
+}
+
+func (p *plainStream) LeaveNested(parents []string, key string) error {
+	return nil
+}
+
+func (p *plainStream) Close() error {
+	return p.out.Flush()
+}
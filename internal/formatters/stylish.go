@@ -1,8 +1,8 @@
 package formatters
 
 import (
-	models "code/internal/models"
 	"fmt"
+	models "github.com/jobsboris27/go-project-244/internal/models"
 	"sort"
 	"strings"
 )
@@ -13,6 +13,14 @@ const (
 )
 
 func RenderStylish(diffNodes []*models.DiffNode, depth int) string {
+	return renderStylish(diffNodes, depth, noColors)
+}
+
+func RenderStylishWithOptions(diffNodes []*models.DiffNode, opts Options) string {
+	return renderStylish(diffNodes, 0, paletteFor(opts))
+}
+
+func renderStylish(diffNodes []*models.DiffNode, depth int, colors palette) string {
 	var result strings.Builder
 
 	if depth == 0 {
@@ -20,30 +28,7 @@ func RenderStylish(diffNodes []*models.DiffNode, depth int) string {
 	}
 
 	for _, node := range diffNodes {
-		switch node.Status {
-		case UNCHANGED:
-			indent := strings.Repeat(" ", depth*IndentSize+IndentSize)
-			result.WriteString(fmt.Sprintf("%s%s: %s\n", indent, node.Key, formatValue(node.OldValue, depth+1)))
-
-		case ADDED:
-			indent := strings.Repeat(" ", depth*IndentSize+SignOffset)
-			result.WriteString(fmt.Sprintf("%s+ %s: %s\n", indent, node.Key, formatValue(node.NewValue, depth+1)))
-
-		case REMOVED:
-			indent := strings.Repeat(" ", depth*IndentSize+SignOffset)
-			result.WriteString(fmt.Sprintf("%s- %s: %s\n", indent, node.Key, formatValue(node.OldValue, depth+1)))
-
-		case MODIFIED:
-			indent := strings.Repeat(" ", depth*IndentSize+SignOffset)
-			result.WriteString(fmt.Sprintf("%s- %s: %s\n", indent, node.Key, formatValue(node.OldValue, depth+1)))
-			result.WriteString(fmt.Sprintf("%s+ %s: %s\n", indent, node.Key, formatValue(node.NewValue, depth+1)))
-
-		case NESTED:
-			indent := strings.Repeat(" ", depth*IndentSize+IndentSize)
-			result.WriteString(fmt.Sprintf("%s%s: {\n", indent, node.Key))
-			result.WriteString(RenderStylish(node.Children, depth+1))
-			result.WriteString(fmt.Sprintf("%s}\n", indent))
-		}
+		result.WriteString(stylishNode(node, depth, colors))
 	}
 
 	if depth == 0 {
@@ -53,6 +38,42 @@ func RenderStylish(diffNodes []*models.DiffNode, depth int) string {
 	return result.String()
 }
 
+func stylishNode(node *models.DiffNode, depth int, colors palette) string {
+	var result strings.Builder
+
+	switch node.Status {
+	case UNCHANGED:
+		indent := strings.Repeat(" ", depth*IndentSize+IndentSize)
+		line := fmt.Sprintf("%s%s: %s", indent, node.Key, formatValue(node.OldValue, depth+1))
+		result.WriteString(colors.paint(UNCHANGED, line) + "\n")
+
+	case ADDED:
+		indent := strings.Repeat(" ", depth*IndentSize+SignOffset)
+		line := fmt.Sprintf("%s+ %s: %s", indent, node.Key, formatValue(node.NewValue, depth+1))
+		result.WriteString(colors.paint(ADDED, line) + "\n")
+
+	case REMOVED:
+		indent := strings.Repeat(" ", depth*IndentSize+SignOffset)
+		line := fmt.Sprintf("%s- %s: %s", indent, node.Key, formatValue(node.OldValue, depth+1))
+		result.WriteString(colors.paint(REMOVED, line) + "\n")
+
+	case MODIFIED:
+		indent := strings.Repeat(" ", depth*IndentSize+SignOffset)
+		oldLine := fmt.Sprintf("%s- %s: %s", indent, node.Key, formatValue(node.OldValue, depth+1))
+		newLine := fmt.Sprintf("%s+ %s: %s", indent, node.Key, formatValue(node.NewValue, depth+1))
+		result.WriteString(colors.paint(MODIFIED, oldLine) + "\n")
+		result.WriteString(colors.paint(MODIFIED, newLine) + "\n")
+
+	case NESTED:
+		indent := strings.Repeat(" ", depth*IndentSize+IndentSize)
+		result.WriteString(fmt.Sprintf("%s%s: {\n", indent, node.Key))
+		result.WriteString(renderStylish(node.Children, depth+1, colors))
+		result.WriteString(fmt.Sprintf("%s}\n", indent))
+	}
+
+	return result.String()
+}
+
 func formatValue(value interface{}, depth int) string {
 	if value == nil {
 		return "null"
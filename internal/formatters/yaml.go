@@ -0,0 +1,26 @@
+package formatters
+
+import (
+	"bytes"
+	"fmt"
+	models "github.com/jobsboris27/go-project-244/internal/models"
+
+	"gopkg.in/yaml.v3"
+)
+
+const YAML = "yaml"
+
+func RenderYAML(diffNodes []*models.DiffNode, opts Options) (string, error) {
+	var result bytes.Buffer
+
+	encoder := yaml.NewEncoder(&result)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(diffDocument(diffNodes, opts)); err != nil {
+		return "", fmt.Errorf("render yaml: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return "", fmt.Errorf("render yaml: %w", err)
+	}
+
+	return string(bytes.TrimSuffix(result.Bytes(), []byte("\n"))), nil
+}
@@ -1,9 +1,40 @@
 package models
 
 type DiffNode struct {
-	Key      string
-	Status   string
-	OldValue interface{}
-	NewValue interface{}
-	Children []*DiffNode
+	Key         string
+	Status      string
+	OldValue    interface{}
+	NewValue    interface{}
+	Children    []*DiffNode
+	OldPosition *Position
+	NewPosition *Position
+}
+
+func (n *DiffNode) Position() *Position {
+	if n.NewPosition != nil {
+		return n.NewPosition
+	}
+	return n.OldPosition
+}
+
+type Diff []*DiffNode
+
+func (d Diff) HasChanges() bool {
+	for _, node := range d {
+		switch node.Status {
+		case "unchanged":
+			continue
+		case "nested":
+			if Diff(node.Children).HasChanges() {
+				return true
+			}
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+func (d Diff) Equal() bool {
+	return !d.HasChanges()
 }
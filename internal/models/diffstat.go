@@ -0,0 +1,18 @@
+package models
+
+type DiffStat struct {
+	Key       string
+	Added     int
+	Removed   int
+	Modified  int
+	Unchanged int
+	Children  []*DiffStat
+}
+
+func (s *DiffStat) Changed() int {
+	return s.Added + s.Removed + s.Modified
+}
+
+func (s *DiffStat) Total() int {
+	return s.Changed() + s.Unchanged
+}
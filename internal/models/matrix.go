@@ -0,0 +1,42 @@
+package models
+
+type Matrix struct {
+	Files    []string
+	Baseline int
+	Rows     []*MatrixRow
+}
+
+type MatrixRow struct {
+	Path    string
+	Cells   []MatrixCell
+	Differs bool
+}
+
+type MatrixCell struct {
+	Present bool
+	Value   interface{}
+	Differs bool
+}
+
+func (m *Matrix) HasBaseline() bool {
+	return m.Baseline >= 0 && m.Baseline < len(m.Files)
+}
+
+func (m *Matrix) HasDifferences() bool {
+	for _, row := range m.Rows {
+		if row.Differs {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Matrix) DifferingRows() int {
+	count := 0
+	for _, row := range m.Rows {
+		if row.Differs {
+			count++
+		}
+	}
+	return count
+}
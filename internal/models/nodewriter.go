@@ -0,0 +1,7 @@
+package models
+
+type NodeWriter interface {
+	EnterNested(parents []string, key string) error
+	WriteNode(parents []string, node *DiffNode) error
+	LeaveNested(parents []string, key string) error
+}
@@ -0,0 +1,16 @@
+package models
+
+import "fmt"
+
+type Position struct {
+	File   string `json:"file,omitempty" yaml:"file,omitempty"`
+	Line   int    `json:"line" yaml:"line"`
+	Column int    `json:"column" yaml:"column"`
+}
+
+func (p *Position) String() string {
+	if p.File == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
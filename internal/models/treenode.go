@@ -1,7 +1,91 @@
 package models
 
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+const linearChildren = 8
+
 type TreeNode struct {
 	Key      string
 	Value    interface{}
 	Children []*TreeNode
+	Position *Position
+
+	index map[string]*TreeNode
+	hash  [sha256.Size]byte
+}
+
+func NewTreeNode(key string, value interface{}, children []*TreeNode) *TreeNode {
+	node := &TreeNode{Key: key, Value: value, Children: children}
+
+	hash := sha256.New()
+	if len(children) == 0 {
+		hash.Write([]byte{'L'})
+		hash.Write([]byte(valueText(value)))
+		hash.Sum(node.hash[:0])
+		return node
+	}
+
+	sort.SliceStable(children, func(i, j int) bool { return children[i].Key < children[j].Key })
+	hash.Write([]byte{'N'})
+	for _, child := range children {
+		hash.Write(binary.AppendUvarint(nil, uint64(len(child.Key))))
+		hash.Write([]byte(child.Key))
+		hash.Write(child.hash[:])
+	}
+	hash.Sum(node.hash[:0])
+
+	if len(children) > linearChildren {
+		node.index = make(map[string]*TreeNode, len(children))
+		for _, child := range children {
+			if _, exists := node.index[child.Key]; !exists {
+				node.index[child.Key] = child
+			}
+		}
+	}
+	return node
+}
+
+func (n *TreeNode) Child(key string) *TreeNode {
+	if n == nil {
+		return nil
+	}
+
+	if n.index != nil {
+		return n.index[key]
+	}
+	for _, child := range n.Children {
+		if child.Key == key {
+			return child
+		}
+	}
+	return nil
+}
+
+func (n *TreeNode) Hash() [sha256.Size]byte {
+	return n.hash
+}
+
+func valueText(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case nil:
+		return "<nil>"
+	default:
+		return fmt.Sprintf("%v", v)
+	}
 }
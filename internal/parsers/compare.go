@@ -0,0 +1,165 @@
+package parsers
+
+import (
+	"fmt"
+	"github.com/jobsboris27/go-project-244/internal/models"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Comparator func(a, b interface{}) bool
+
+var comparators = map[string]Comparator{
+	"numeric":          compareNumeric,
+	"case-insensitive": compareCaseInsensitive,
+	"trim-space":       compareTrimSpace,
+}
+
+func Comparators() []string {
+	names := make([]string, 0, len(comparators))
+	for name := range comparators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func ApplyComparators(diff models.Diff, rules map[string]string) (models.Diff, error) {
+	if len(rules) == 0 {
+		return diff, nil
+	}
+	if err := ValidateComparators(rules); err != nil {
+		return nil, err
+	}
+	return applyComparators(diff, rules, ""), nil
+}
+
+func ValidateComparators(rules map[string]string) error {
+	for pattern, name := range rules {
+		if _, ok := comparators[name]; !ok {
+			return fmt.Errorf("unknown comparator %q for %s (available: %s)",
+				name, pattern, strings.Join(Comparators(), ", "))
+		}
+	}
+	return nil
+}
+
+func applyComparators(diff models.Diff, rules map[string]string, prefix string) models.Diff {
+	result := make(models.Diff, 0, len(diff))
+
+	for _, node := range diff {
+		nodePath := joinPath(prefix, node.Key)
+
+		switch {
+		case node.Status == "nested":
+			compared := *node
+			compared.Children = applyComparators(node.Children, rules, nodePath)
+			node = &compared
+		case node.Status == "modified" && isScalar(node.OldValue) && isScalar(node.NewValue):
+			if compare := comparatorFor(nodePath, rules); compare != nil && compare(node.OldValue, node.NewValue) {
+				compared := *node
+				compared.Status = "unchanged"
+				compared.NewValue = nil
+				node = &compared
+			}
+		}
+
+		result = append(result, node)
+	}
+
+	return result
+}
+
+func comparatorFor(keyPath string, rules map[string]string) Comparator {
+	best, found := "", false
+	for pattern := range rules {
+		if MatchPath(pattern, keyPath) && (!found || moreSpecific(pattern, best)) {
+			best, found = pattern, true
+		}
+	}
+	if !found {
+		return nil
+	}
+	return comparators[rules[best]]
+}
+
+func moreSpecific(a, b string) bool {
+	aParts, bParts := strings.Split(a, "."), strings.Split(b, ".")
+	for i := range min(len(aParts), len(bParts)) {
+		if x, y := literalLength(aParts[i]), literalLength(bParts[i]); x != y {
+			return x > y
+		}
+	}
+	return a < b
+}
+
+func literalLength(segment string) int {
+	if !strings.ContainsAny(segment, `*?[\`) {
+		return len(segment) + 1
+	}
+	return len(segment) - strings.Count(segment, "*") - strings.Count(segment, "?")
+}
+
+func sameValue(a, b interface{}) bool {
+	if models.ValueKind(a) != models.ValueKind(b) {
+		return false
+	}
+
+	switch x := a.(type) {
+	case map[string]interface{}:
+		y := b.(map[string]interface{})
+		if len(x) != len(y) {
+			return false
+		}
+		for key, value := range x {
+			other, ok := y[key]
+			if !ok || !sameValue(value, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		y := b.([]interface{})
+		if len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !sameValue(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return fmt.Sprintf("%v", a) == fmt.Sprintf("%v", b)
+	}
+}
+
+func isScalar(value interface{}) bool {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		return false
+	default:
+		return true
+	}
+}
+
+func compareNumeric(a, b interface{}) bool {
+	x, err := strconv.ParseFloat(fmt.Sprintf("%v", a), 64)
+	if err != nil {
+		return false
+	}
+	y, err := strconv.ParseFloat(fmt.Sprintf("%v", b), 64)
+	if err != nil {
+		return false
+	}
+	return x == y
+}
+
+func compareCaseInsensitive(a, b interface{}) bool {
+	return strings.EqualFold(fmt.Sprintf("%v", a), fmt.Sprintf("%v", b))
+}
+
+func compareTrimSpace(a, b interface{}) bool {
+	return strings.TrimSpace(fmt.Sprintf("%v", a)) == strings.TrimSpace(fmt.Sprintf("%v", b))
+}
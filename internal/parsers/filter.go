@@ -0,0 +1,104 @@
+package parsers
+
+import (
+	"github.com/jobsboris27/go-project-244/internal/models"
+	"path"
+	"strings"
+)
+
+func OnlyPaths(diff models.Diff, paths []string) models.Diff {
+	if len(paths) == 0 {
+		return diff
+	}
+	return onlyPaths(diff, paths, "")
+}
+
+func onlyPaths(diff models.Diff, paths []string, prefix string) models.Diff {
+	var result models.Diff
+
+	for _, node := range diff {
+		nodePath := joinPath(prefix, node.Key)
+
+		switch {
+		case isSelected(nodePath, paths):
+			result = append(result, node)
+		case node.Status == "nested" && isAncestor(nodePath, paths):
+			children := onlyPaths(node.Children, paths, nodePath)
+			if len(children) == 0 {
+				continue
+			}
+			filtered := *node
+			filtered.Children = children
+			result = append(result, &filtered)
+		case node.Status != "nested" && isAncestor(nodePath, paths):
+			filtered := filterObjects(node, func(value map[string]interface{}) map[string]interface{} {
+				return onlyValue(value, paths, nodePath)
+			})
+			if hasEntries(filtered.OldValue) || hasEntries(filtered.NewValue) {
+				result = append(result, filtered)
+			}
+		}
+	}
+
+	return result
+}
+
+func onlyValue(value map[string]interface{}, paths []string, prefix string) map[string]interface{} {
+	result := map[string]interface{}{}
+
+	for key, item := range value {
+		itemPath := joinPath(prefix, key)
+		nested, ok := item.(map[string]interface{})
+
+		switch {
+		case isSelected(itemPath, paths):
+			result[key] = item
+		case ok && isAncestor(itemPath, paths):
+			if nested = onlyValue(nested, paths, itemPath); len(nested) > 0 {
+				result[key] = nested
+			}
+		}
+	}
+
+	return result
+}
+
+func hasEntries(value interface{}) bool {
+	object, ok := value.(map[string]interface{})
+	return ok && len(object) > 0
+}
+
+func MatchPath(pattern, keyPath string) bool {
+	patternParts := strings.Split(pattern, ".")
+	pathParts := strings.Split(keyPath, ".")
+	if len(patternParts) != len(pathParts) {
+		return false
+	}
+
+	for i, part := range patternParts {
+		if ok, _ := path.Match(part, pathParts[i]); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func isSelected(keyPath string, paths []string) bool {
+	for _, selected := range paths {
+		if MatchPath(selected, keyPath) {
+			return true
+		}
+	}
+	return false
+}
+
+func isAncestor(keyPath string, paths []string) bool {
+	depth := strings.Count(keyPath, ".") + 1
+	for _, selected := range paths {
+		parts := strings.Split(selected, ".")
+		if len(parts) > depth && MatchPath(strings.Join(parts[:depth], "."), keyPath) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package parsers
+
+import (
+	"github.com/jobsboris27/go-project-244/internal/models"
+)
+
+func IgnorePaths(diff models.Diff, paths []string) models.Diff {
+	if len(paths) == 0 {
+		return diff
+	}
+	return ignorePaths(diff, paths, "")
+}
+
+func ignorePaths(diff models.Diff, paths []string, prefix string) models.Diff {
+	var result models.Diff
+
+	for _, node := range diff {
+		path := joinPath(prefix, node.Key)
+
+		if isIgnored(path, paths) {
+			continue
+		}
+
+		if node.Status == "nested" {
+			children := ignorePaths(node.Children, paths, path)
+			if len(children) == 0 {
+				continue
+			}
+			filtered := *node
+			filtered.Children = children
+			node = &filtered
+		} else {
+			filtered := filterObjects(node, func(value map[string]interface{}) map[string]interface{} {
+				return ignoreValue(value, paths, path)
+			})
+			if node.Status != "modified" && isEmptyObject(nodeValue(filtered)) && !isEmptyObject(nodeValue(node)) {
+				continue
+			}
+			node = filtered
+		}
+
+		result = append(result, node)
+	}
+
+	return result
+}
+
+func ignoreValue(value map[string]interface{}, paths []string, prefix string) map[string]interface{} {
+	result := make(map[string]interface{}, len(value))
+
+	for key, item := range value {
+		itemPath := joinPath(prefix, key)
+		if isIgnored(itemPath, paths) {
+			continue
+		}
+
+		if nested, ok := item.(map[string]interface{}); ok && len(nested) > 0 {
+			if nested = ignoreValue(nested, paths, itemPath); len(nested) == 0 {
+				continue
+			}
+			item = nested
+		}
+		result[key] = item
+	}
+
+	return result
+}
+
+func isIgnored(keyPath string, paths []string) bool {
+	return isSelected(keyPath, paths) || isUnderSelected(keyPath, paths)
+}
+
+func filterObjects(node *models.DiffNode, filter func(map[string]interface{}) map[string]interface{}) *models.DiffNode {
+	oldValue, oldObject := node.OldValue.(map[string]interface{})
+	newValue, newObject := node.NewValue.(map[string]interface{})
+	if !oldObject && !newObject {
+		return node
+	}
+
+	filtered := *node
+	if oldObject {
+		filtered.OldValue = filter(oldValue)
+	}
+	if newObject {
+		filtered.NewValue = filter(newValue)
+	}
+	return &filtered
+}
+
+func nodeValue(node *models.DiffNode) interface{} {
+	if node.Status == "added" {
+		return node.NewValue
+	}
+	return node.OldValue
+}
+
+func isEmptyObject(value interface{}) bool {
+	object, ok := value.(map[string]interface{})
+	return ok && len(object) == 0
+}
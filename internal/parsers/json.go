@@ -1,27 +1,133 @@
 package parsers
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"github.com/jobsboris27/go-project-244/internal/models"
+	"io"
 	"os"
+	"strings"
 )
 
+type jsonParser struct{}
+
+func (jsonParser) Parse(r io.Reader) (any, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+func (jsonParser) Extensions() []string {
+	return []string{JSON_EXT}
+}
+
+func (jsonParser) MIMETypes() []string {
+	return []string{"application/json", "text/json"}
+}
+
+func (jsonParser) Sniff(head []byte) bool {
+	head = bytes.TrimLeft(head, " \t\r\n\ufeff")
+	return len(head) > 0 && (head[0] == '{' || head[0] == '[')
+}
+
 func ParseJSON(path string) (map[string]interface{}, error) {
 	file, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
 
-	var raw interface{}
-	if err := json.Unmarshal(file, &raw); err != nil {
+	return ParseReader(bytes.NewReader(file), jsonParser{})
+}
+
+func (jsonParser) Locate(data []byte) (map[string]models.Position, error) {
+	positions := make(map[string]models.Position)
+	locator := &jsonLocator{
+		decoder:   json.NewDecoder(bytes.NewReader(data)),
+		data:      data,
+		lines:     newLineIndex(data),
+		positions: positions,
+	}
+
+	token, err := locator.decoder.Token()
+	if err != nil {
 		return nil, err
 	}
+	if token == json.Delim('{') {
+		err = locator.walkObject("")
+	} else {
+		err = locator.walkToken(token, "root")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return positions, nil
+}
 
-	switch v := raw.(type) {
-	case map[string]interface{}:
-		return v, nil
-	case []interface{}:
-		return map[string]interface{}{"root": v}, nil
+type jsonLocator struct {
+	decoder   *json.Decoder
+	data      []byte
+	lines     lineIndex
+	positions map[string]models.Position
+}
+
+func (l *jsonLocator) walkToken(token json.Token, path string) error {
+	switch token {
+	case json.Delim('{'):
+		return l.walkObject(path)
+	case json.Delim('['):
+		return l.walkArray(path)
 	default:
-		return map[string]interface{}{"root": v}, nil
+		return nil
+	}
+}
+
+func (l *jsonLocator) walkObject(path string) error {
+	for l.decoder.More() {
+		offset := l.nextOffset()
+		key, err := l.decoder.Token()
+		if err != nil {
+			return err
+		}
+		if err := l.walkValue(joinPath(path, fmt.Sprint(key)), offset); err != nil {
+			return err
+		}
+	}
+	_, err := l.decoder.Token()
+	return err
+}
+
+func (l *jsonLocator) walkArray(path string) error {
+	for i := 0; l.decoder.More(); i++ {
+		if err := l.walkValue(joinPath(path, fmt.Sprintf("[%d]", i)), l.nextOffset()); err != nil {
+			return err
+		}
+	}
+	_, err := l.decoder.Token()
+	return err
+}
+
+func (l *jsonLocator) walkValue(path string, offset int) error {
+	l.positions[path] = l.lines.position(offset)
+
+	token, err := l.decoder.Token()
+	if err != nil {
+		return err
+	}
+	return l.walkToken(token, path)
+}
+
+func (l *jsonLocator) nextOffset() int {
+	offset := int(l.decoder.InputOffset())
+	for offset < len(l.data) && strings.IndexByte(" \t\r\n,", l.data[offset]) >= 0 {
+		offset++
 	}
+	return offset
 }
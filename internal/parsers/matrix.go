@@ -0,0 +1,107 @@
+package parsers
+
+import (
+	"github.com/jobsboris27/go-project-244/internal/models"
+	"sort"
+	"strings"
+)
+
+type MatrixOptions struct {
+	Baseline    int
+	Ignore      []string
+	Only        []string
+	Comparators map[string]string
+}
+
+func BuildMatrix(files []string, trees []*models.TreeNode, opts MatrixOptions) *models.Matrix {
+	matrix := &models.Matrix{Files: files, Baseline: opts.Baseline}
+	buildMatrixRows(matrix, trees, "", opts)
+	return matrix
+}
+
+func buildMatrixRows(matrix *models.Matrix, nodes []*models.TreeNode, prefix string, opts MatrixOptions) {
+	for _, key := range collectMatrixKeys(nodes) {
+		path := joinPath(prefix, key)
+		if isIgnored(path, opts.Ignore) {
+			continue
+		}
+
+		children := make([]*models.TreeNode, len(nodes))
+		allNested := true
+		for i, node := range nodes {
+			children[i] = node.Child(key)
+			if children[i] != nil && !hasChildren(children[i]) {
+				allNested = false
+			}
+		}
+
+		if allNested {
+			buildMatrixRows(matrix, children, path, opts)
+			continue
+		}
+		if len(opts.Only) > 0 && !isSelected(path, opts.Only) && !isUnderSelected(path, opts.Only) {
+			continue
+		}
+		matrix.Rows = append(matrix.Rows, buildMatrixRow(path, children, opts))
+	}
+}
+
+func buildMatrixRow(path string, nodes []*models.TreeNode, opts MatrixOptions) *models.MatrixRow {
+	row := &models.MatrixRow{Path: path, Cells: make([]models.MatrixCell, len(nodes))}
+	for i, node := range nodes {
+		if node != nil {
+			row.Cells[i] = models.MatrixCell{Present: true, Value: reconstructObject(node)}
+		}
+	}
+
+	reference := 0
+	if opts.Baseline >= 0 && opts.Baseline < len(nodes) {
+		reference = opts.Baseline
+	}
+	for i := range row.Cells {
+		if !cellsEqual(path, row.Cells[reference], row.Cells[i], opts.Comparators) {
+			row.Cells[i].Differs = true
+			row.Differs = true
+		}
+	}
+	return row
+}
+
+func cellsEqual(path string, a, b models.MatrixCell, rules map[string]string) bool {
+	if a.Present != b.Present {
+		return false
+	}
+	if sameValue(a.Value, b.Value) {
+		return true
+	}
+	compare := comparatorFor(path, rules)
+	return compare != nil && isScalar(a.Value) && isScalar(b.Value) && compare(a.Value, b.Value)
+}
+
+func collectMatrixKeys(nodes []*models.TreeNode) []string {
+	seen := map[string]bool{}
+	var keys []string
+	for _, node := range nodes {
+		if node == nil {
+			continue
+		}
+		for _, child := range node.Children {
+			if !seen[child.Key] {
+				seen[child.Key] = true
+				keys = append(keys, child.Key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func isUnderSelected(path string, paths []string) bool {
+	parts := strings.Split(path, ".")
+	for i := 1; i < len(parts); i++ {
+		if isSelected(strings.Join(parts[:i], "."), paths) {
+			return true
+		}
+	}
+	return false
+}
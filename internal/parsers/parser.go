@@ -1,24 +1,246 @@
 package parsers
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"mime"
+	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 )
 
 const (
 	YAML_EXT       = ".yaml"
 	YAML_EXT_SHORT = ".yml"
 	JSON_EXT       = ".json"
+
+	sniffSize = 512
+)
+
+type Parser interface {
+	Parse(r io.Reader) (any, error)
+	Extensions() []string
+	MIMETypes() []string
+	Sniff(head []byte) bool
+}
+
+type registeredParser struct {
+	name   string
+	parser Parser
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []registeredParser
 )
 
+func init() {
+	for name, parser := range map[string]Parser{"json": jsonParser{}, "yaml": yamlParser{}} {
+		if err := Register(name, parser); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func Register(name string, parser Parser) error {
+	if name == "" {
+		return fmt.Errorf("parser name must not be empty")
+	}
+	if parser == nil {
+		return fmt.Errorf("parser %q is nil", name)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for _, registered := range registry {
+		if registered.name == name {
+			return fmt.Errorf("parser %q is already registered", name)
+		}
+		for _, ext := range parser.Extensions() {
+			if containsFold(registered.parser.Extensions(), ext) {
+				return fmt.Errorf("extension %s is already handled by parser %q", ext, registered.name)
+			}
+		}
+	}
+
+	registry = append(registry, registeredParser{name: name, parser: parser})
+	sort.Slice(registry, func(i, j int) bool {
+		return registry[i].name < registry[j].name
+	})
+	return nil
+}
+
+func Unregister(name string) bool {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for i, registered := range registry {
+		if registered.name == name {
+			registry = append(registry[:i:i], registry[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for _, registered := range registry {
+		names = append(names, registered.name)
+	}
+	return names
+}
+
+func Extensions() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	var exts []string
+	for _, registered := range registry {
+		exts = append(exts, registered.parser.Extensions()...)
+	}
+	sort.Strings(exts)
+	return exts
+}
+
+func ForName(name string) (Parser, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	for _, registered := range registry {
+		if registered.name == name {
+			return registered.parser, true
+		}
+	}
+	return nil, false
+}
+
+func ForExtension(ext string) (Parser, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	for _, registered := range registry {
+		if containsFold(registered.parser.Extensions(), ext) {
+			return registered.parser, true
+		}
+	}
+	return nil, false
+}
+
+func ForMIMEType(mimeType string) (Parser, bool) {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return nil, false
+	}
+
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	for _, registered := range registry {
+		if containsFold(registered.parser.MIMETypes(), mediaType) {
+			return registered.parser, true
+		}
+	}
+	return nil, false
+}
+
+func Sniff(head []byte) (Parser, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	for _, registered := range registry {
+		if registered.parser.Sniff(head) {
+			return registered.parser, true
+		}
+	}
+	return nil, false
+}
+
 func ParseByExtension(path string) (map[string]interface{}, error) {
 	ext := filepath.Ext(path)
-	switch ext {
-	case JSON_EXT:
-		return ParseJSON(path)
-	case YAML_EXT, YAML_EXT_SHORT:
-		return ParseYAML(path)
-	default:
+	parser, ok := ForExtension(ext)
+	if !ok {
 		return nil, fmt.Errorf("unsupported file extension: %s", ext)
 	}
+	return parseFile(path, parser)
+}
+
+func ParseFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	parser, err := ResolveParser(path, "", data)
+	if err != nil {
+		return nil, err
+	}
+	return ParseReader(bytes.NewReader(data), parser)
+}
+
+func ResolveParser(path, mimeType string, data []byte) (Parser, error) {
+	ext := filepath.Ext(path)
+	if parser, ok := ForExtension(ext); ok {
+		return parser, nil
+	}
+	if parser, ok := ForMIMEType(mimeType); ok && mimeType != "" {
+		return parser, nil
+	}
+	if parser, ok := Sniff(data[:min(len(data), sniffSize)]); ok {
+		return parser, nil
+	}
+	return nil, fmt.Errorf("unsupported file extension: %s", ext)
+}
+
+func ParseReader(r io.Reader, parser Parser) (map[string]interface{}, error) {
+	raw, err := parser.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+	return ToDocument(raw), nil
+}
+
+func parseFile(path string, parser Parser) (map[string]interface{}, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ParseReader(file, parser)
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func ToDocument(raw interface{}) map[string]interface{} {
+	switch v := raw.(type) {
+	case map[string]interface{}:
+		return v
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{})
+		for k, val := range v {
+			if strKey, ok := k.(string); ok {
+				converted[strKey] = val
+			} else {
+				converted[fmt.Sprintf("%v", k)] = val
+			}
+		}
+		return converted
+	default:
+		return map[string]interface{}{"root": v}
+	}
 }
@@ -0,0 +1,89 @@
+package parsers
+
+import (
+	"bytes"
+	"github.com/jobsboris27/go-project-244/internal/models"
+	"os"
+	"sort"
+)
+
+type Locator interface {
+	Locate(data []byte) (map[string]models.Position, error)
+}
+
+func LoadTree(path string) (*models.TreeNode, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	parser, err := ResolveParser(path, "", data)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTree(data, path, parser)
+}
+
+func ParseTree(data []byte, file string, parser Parser) (*models.TreeNode, error) {
+	raw, err := parser.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := ConvertValueToTree(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if locator, ok := parser.(Locator); ok {
+		positions, err := locator.Locate(data)
+		if err != nil {
+			return nil, err
+		}
+		AttachPositions(tree, positions, file)
+	}
+
+	return tree, nil
+}
+
+func AttachPositions(tree *models.TreeNode, positions map[string]models.Position, file string) {
+	if len(positions) == 0 {
+		return
+	}
+	attachPositions(tree.Children, positions, file, "")
+}
+
+func attachPositions(nodes []*models.TreeNode, positions map[string]models.Position, file, path string) {
+	for _, node := range nodes {
+		nodePath := joinPath(path, node.Key)
+		if position, ok := positions[nodePath]; ok {
+			position.File = file
+			node.Position = &position
+		}
+		attachPositions(node.Children, positions, file, nodePath)
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+type lineIndex []int
+
+func newLineIndex(data []byte) lineIndex {
+	index := lineIndex{0}
+	for i, b := range data {
+		if b == '\n' {
+			index = append(index, i+1)
+		}
+	}
+	return index
+}
+
+func (index lineIndex) position(offset int) models.Position {
+	line := sort.Search(len(index), func(i int) bool { return index[i] > offset })
+	return models.Position{Line: line, Column: offset - index[line-1] + 1}
+}
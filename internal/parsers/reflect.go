@@ -0,0 +1,279 @@
+package parsers
+
+import (
+	"encoding"
+	"encoding/base64"
+	"fmt"
+	"github.com/jobsboris27/go-project-244/internal/models"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+func ConvertValueToTree(value any) (*models.TreeNode, error) {
+	normalized, err := NormalizeValue(value)
+	if err != nil {
+		return nil, err
+	}
+	return СonvertMapToTree(ToDocument(normalized)), nil
+}
+
+type reference struct {
+	ptr    uintptr
+	typ    reflect.Type
+	length int
+}
+
+type normalizer struct {
+	visiting map[reference]bool
+}
+
+func NormalizeValue(value any) (interface{}, error) {
+	n := &normalizer{visiting: map[reference]bool{}}
+	return n.normalizeValue(reflect.ValueOf(value))
+}
+
+func (n *normalizer) normalizeValue(v reflect.Value) (interface{}, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+
+	if v.Type() == timeType && v.CanInterface() {
+		return v.Interface().(time.Time).Format(time.RFC3339Nano), nil
+	}
+	if text, ok, err := marshalText(v); ok {
+		return text, err
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return n.normalizeValue(v.Elem())
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return n.visit(v, func() (interface{}, error) { return n.normalizeValue(v.Elem()) })
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return n.visit(v, func() (interface{}, error) { return n.normalizeMap(v) })
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return base64.StdEncoding.EncodeToString(v.Bytes()), nil
+		}
+		return n.visit(v, func() (interface{}, error) { return n.normalizeList(v) })
+	case reflect.Array:
+		return n.normalizeList(v)
+	case reflect.Struct:
+		result := make(map[string]interface{})
+		if err := n.normalizeStruct(v, result); err != nil {
+			return nil, err
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("unsupported value of type %s", v.Type())
+	}
+}
+
+func marshalText(v reflect.Value) (string, bool, error) {
+	if !v.CanInterface() || v.Kind() == reflect.Pointer && v.IsNil() {
+		return "", false, nil
+	}
+
+	var marshaler encoding.TextMarshaler
+	switch {
+	case v.Type().Implements(textMarshalerType):
+		marshaler = v.Interface().(encoding.TextMarshaler)
+	case v.CanAddr() && reflect.PointerTo(v.Type()).Implements(textMarshalerType):
+		marshaler = v.Addr().Interface().(encoding.TextMarshaler)
+	default:
+		return "", false, nil
+	}
+
+	text, err := marshaler.MarshalText()
+	if err != nil {
+		return "", true, fmt.Errorf("marshalling %s: %w", v.Type(), err)
+	}
+	return string(text), true, nil
+}
+
+func (n *normalizer) visit(v reflect.Value, normalize func() (interface{}, error)) (interface{}, error) {
+	ref := reference{ptr: v.Pointer(), typ: v.Type()}
+	if v.Kind() == reflect.Slice {
+		ref.length = v.Len()
+	}
+	if n.visiting[ref] {
+		return nil, fmt.Errorf("cycle through value of type %s", v.Type())
+	}
+
+	n.visiting[ref] = true
+	defer delete(n.visiting, ref)
+	return normalize()
+}
+
+func (n *normalizer) normalizeMap(v reflect.Value) (interface{}, error) {
+	result := make(map[string]interface{}, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		key, err := n.normalizeValue(iter.Key())
+		if err != nil {
+			return nil, err
+		}
+		value, err := n.normalizeValue(iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		result[fmt.Sprintf("%v", key)] = value
+	}
+	return result, nil
+}
+
+func (n *normalizer) normalizeList(v reflect.Value) (interface{}, error) {
+	result := make([]interface{}, v.Len())
+	for i := range result {
+		item, err := n.normalizeValue(v.Index(i))
+		if err != nil {
+			return nil, err
+		}
+		result[i] = item
+	}
+	return result, nil
+}
+
+func (n *normalizer) normalizeStruct(v reflect.Value, result map[string]interface{}) error {
+	var embedded []reflect.Value
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		name, opts, skip := fieldTag(field)
+		if skip {
+			continue
+		}
+
+		fieldValue := v.Field(i)
+		if isEmbeddedStruct(field, name, opts) {
+			if fieldValue.Kind() == reflect.Pointer && fieldValue.IsNil() {
+				continue
+			}
+			embedded = append(embedded, fieldValue)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+		if hasOption(opts, "omitempty") && isEmptyValue(fieldValue) {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		value, err := n.normalizeValue(fieldValue)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		result[name] = value
+	}
+
+	for _, value := range embedded {
+		promoted := make(map[string]interface{})
+		if err := n.normalizeEmbedded(value, promoted); err != nil {
+			return err
+		}
+		for key, val := range promoted {
+			if _, exists := result[key]; !exists {
+				result[key] = val
+			}
+		}
+	}
+
+	return nil
+}
+
+func (n *normalizer) normalizeEmbedded(v reflect.Value, result map[string]interface{}) error {
+	if v.Kind() != reflect.Pointer {
+		return n.normalizeStruct(v, result)
+	}
+	_, err := n.visit(v, func() (interface{}, error) {
+		return nil, n.normalizeStruct(v.Elem(), result)
+	})
+	return err
+}
+
+func fieldTag(field reflect.StructField) (string, []string, bool) {
+	tag, ok := field.Tag.Lookup("json")
+	if !ok {
+		tag, ok = field.Tag.Lookup("yaml")
+	}
+	if !ok {
+		return "", nil, false
+	}
+	if tag == "-" {
+		return "", nil, true
+	}
+
+	parts := strings.Split(tag, ",")
+	return parts[0], parts[1:], false
+}
+
+func isEmbeddedStruct(field reflect.StructField, name string, opts []string) bool {
+	fieldType := field.Type
+	if fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
+	}
+	if fieldType.Kind() != reflect.Struct || fieldType == timeType {
+		return false
+	}
+	return hasOption(opts, "inline") || field.Anonymous && name == ""
+}
+
+func hasOption(opts []string, option string) bool {
+	for _, opt := range opts {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	default:
+		return false
+	}
+}
@@ -0,0 +1,501 @@
+package parsers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jobsboris27/go-project-244/internal/models"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type StreamOptions struct {
+	Ignore      []string
+	Only        []string
+	Comparators map[string]string
+	ArrayKey    string
+}
+
+type valueKind int
+
+const (
+	scalarValue valueKind = iota
+	objectValue
+	arrayValue
+)
+
+type valueHead struct {
+	kind  valueKind
+	empty bool
+	value interface{}
+}
+
+type streamMerger struct {
+	old     *json.Decoder
+	new     *json.Decoder
+	out     models.NodeWriter
+	opts    StreamOptions
+	pending []string
+	entered int
+	changed bool
+}
+
+var errUnsorted = errors.New("keys must be sorted for streaming")
+
+func StreamDiff(r1, r2 io.Reader, out models.NodeWriter, opts StreamOptions) (bool, error) {
+	if err := ValidateComparators(opts.Comparators); err != nil {
+		return false, err
+	}
+
+	m := &streamMerger{old: json.NewDecoder(r1), new: json.NewDecoder(r2), out: out, opts: opts}
+
+	head1, err := readHead(m.old)
+	if err != nil {
+		return false, fmt.Errorf("reading first document: %w", err)
+	}
+	head2, err := readHead(m.new)
+	if err != nil {
+		return false, fmt.Errorf("reading second document: %w", err)
+	}
+
+	switch {
+	case head1.kind == objectValue && head2.kind == objectValue:
+		err = m.mergeObjects(nil)
+	case head1.kind != objectValue && head2.kind != objectValue:
+		err = m.compare(nil, "root", head1, head2)
+	default:
+		err = errors.New("cannot stream an object against a document that is not one")
+	}
+	return m.changed, err
+}
+
+func (m *streamMerger) mergeObjects(path []string) error {
+	key1, more1, err := nextKey(m.old, "")
+	if err != nil {
+		return err
+	}
+	key2, more2, err := nextKey(m.new, "")
+	if err != nil {
+		return err
+	}
+
+	for more1 || more2 {
+		switch {
+		case more1 && (!more2 || key1 < key2):
+			if err := m.onlyOld(path, key1); err != nil {
+				return err
+			}
+			if key1, more1, err = nextKey(m.old, key1); err != nil {
+				return err
+			}
+		case more2 && (!more1 || key2 < key1):
+			if err := m.onlyNew(path, key2); err != nil {
+				return err
+			}
+			if key2, more2, err = nextKey(m.new, key2); err != nil {
+				return err
+			}
+		default:
+			if err := m.both(path, key1); err != nil {
+				return err
+			}
+			if key1, more1, err = nextKey(m.old, key1); err != nil {
+				return err
+			}
+			if key2, more2, err = nextKey(m.new, key2); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := m.old.Token(); err != nil {
+		return err
+	}
+	_, err = m.new.Token()
+	return err
+}
+
+func (m *streamMerger) mergeArrays(path []string) error {
+	if m.opts.ArrayKey != "" {
+		return m.mergeKeyedArrays(path)
+	}
+
+	for i := 0; m.old.More() || m.new.More(); i++ {
+		key := fmt.Sprintf("[%d]", i)
+		var err error
+		switch {
+		case m.old.More() && m.new.More():
+			err = m.both(path, key)
+		case m.old.More():
+			err = m.onlyOld(path, key)
+		default:
+			err = m.onlyNew(path, key)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, err := m.old.Token(); err != nil {
+		return err
+	}
+	_, err := m.new.Token()
+	return err
+}
+
+func (m *streamMerger) mergeKeyedArrays(path []string) error {
+	item1, key1, err := m.nextItem(m.old, path, "")
+	if err != nil {
+		return err
+	}
+	item2, key2, err := m.nextItem(m.new, path, "")
+	if err != nil {
+		return err
+	}
+
+	for item1 != nil || item2 != nil {
+		switch {
+		case item1 != nil && (item2 == nil || lessKey(key1, key2)):
+			if err := m.leaf(path, &models.DiffNode{Key: key1, Status: "removed", OldValue: treeValue(item1)}); err != nil {
+				return err
+			}
+			if item1, key1, err = m.nextItem(m.old, path, key1); err != nil {
+				return err
+			}
+		case item2 != nil && (item1 == nil || lessKey(key2, key1)):
+			if err := m.leaf(path, &models.DiffNode{Key: key2, Status: "added", NewValue: treeValue(item2)}); err != nil {
+				return err
+			}
+			if item2, key2, err = m.nextItem(m.new, path, key2); err != nil {
+				return err
+			}
+		default:
+			if err := m.writeDiff(path, key1, GetDiff(valueTree(item1), valueTree(item2))); err != nil {
+				return err
+			}
+			if item1, key1, err = m.nextItem(m.old, path, key1); err != nil {
+				return err
+			}
+			if item2, key2, err = m.nextItem(m.new, path, key2); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := m.old.Token(); err != nil {
+		return err
+	}
+	_, err = m.new.Token()
+	return err
+}
+
+func (m *streamMerger) nextItem(dec *json.Decoder, path []string, previous string) (map[string]interface{}, string, error) {
+	if !dec.More() {
+		return nil, "", nil
+	}
+
+	var item interface{}
+	if err := dec.Decode(&item); err != nil {
+		return nil, "", err
+	}
+	object, ok := item.(map[string]interface{})
+	if !ok {
+		return nil, "", fmt.Errorf("array %s: elements must be objects to match them by %q", strings.Join(path, "."), m.opts.ArrayKey)
+	}
+	id, ok := object[m.opts.ArrayKey]
+	if !ok {
+		return nil, "", fmt.Errorf("array %s: element has no %q field", strings.Join(path, "."), m.opts.ArrayKey)
+	}
+
+	key := fmt.Sprintf("[%s=%v]", m.opts.ArrayKey, id)
+	if previous != "" && !lessKey(previous, key) {
+		return nil, "", fmt.Errorf("array %s: %w, %s comes after %s", strings.Join(path, "."), errUnsorted, key, previous)
+	}
+	return object, key, nil
+}
+
+func (m *streamMerger) onlyOld(path []string, key string) error {
+	if !m.wanted(path, key) {
+		return skipValue(m.old)
+	}
+	value, err := readValue(m.old)
+	if err != nil {
+		return err
+	}
+	return m.leaf(path, &models.DiffNode{Key: key, Status: "removed", OldValue: value})
+}
+
+func (m *streamMerger) onlyNew(path []string, key string) error {
+	if !m.wanted(path, key) {
+		return skipValue(m.new)
+	}
+	value, err := readValue(m.new)
+	if err != nil {
+		return err
+	}
+	return m.leaf(path, &models.DiffNode{Key: key, Status: "added", NewValue: value})
+}
+
+func (m *streamMerger) both(path []string, key string) error {
+	if !m.wanted(path, key) && !m.hasWantedChildren(path, key) {
+		if err := skipValue(m.old); err != nil {
+			return err
+		}
+		return skipValue(m.new)
+	}
+
+	head1, err := readHead(m.old)
+	if err != nil {
+		return err
+	}
+	head2, err := readHead(m.new)
+	if err != nil {
+		return err
+	}
+	return m.compare(path, key, head1, head2)
+}
+
+func (m *streamMerger) compare(path []string, key string, head1, head2 valueHead) error {
+	if head1.kind == head2.kind && !head1.isLeaf() && !head2.isLeaf() {
+		childPath := append(append([]string(nil), path...), key)
+		m.pending = append(m.pending, key)
+
+		var err error
+		if head1.kind == objectValue {
+			err = m.mergeObjects(childPath)
+		} else {
+			err = m.mergeArrays(childPath)
+		}
+		if err != nil {
+			return err
+		}
+		return m.leave(path, key)
+	}
+
+	value1, err := finishValue(m.old, head1)
+	if err != nil {
+		return err
+	}
+	value2, err := finishValue(m.new, head2)
+	if err != nil {
+		return err
+	}
+
+	if !head1.isLeaf() && !head2.isLeaf() {
+		return m.writeDiff(path, key, GetDiff(valueTree(value1), valueTree(value2)))
+	}
+	if !m.wanted(path, key) {
+		return nil
+	}
+	if head1.isLeaf() && head2.isLeaf() && fmt.Sprintf("%v", value1) == fmt.Sprintf("%v", value2) {
+		return m.leaf(path, &models.DiffNode{Key: key, Status: "unchanged", OldValue: value1})
+	}
+	return m.leaf(path, &models.DiffNode{Key: key, Status: "modified", OldValue: treeValue(value1), NewValue: treeValue(value2)})
+}
+
+func (m *streamMerger) writeDiff(path []string, key string, diff models.Diff) error {
+	childPath := append(append([]string(nil), path...), key)
+	m.pending = append(m.pending, key)
+
+	for _, node := range diff {
+		switch {
+		case node.Status == "nested":
+			if err := m.writeDiff(childPath, node.Key, node.Children); err != nil {
+				return err
+			}
+		case m.wanted(childPath, node.Key):
+			if err := m.leaf(childPath, node); err != nil {
+				return err
+			}
+		}
+	}
+	return m.leave(path, key)
+}
+
+func (m *streamMerger) leaf(path []string, node *models.DiffNode) error {
+	nodePath := strings.Join(append(append([]string(nil), path...), node.Key), ".")
+	if node.Status == "modified" && isScalar(node.OldValue) && isScalar(node.NewValue) {
+		if compare := comparatorFor(nodePath, m.opts.Comparators); compare != nil && compare(node.OldValue, node.NewValue) {
+			node = &models.DiffNode{Key: node.Key, Status: "unchanged", OldValue: node.OldValue}
+		}
+	}
+
+	if err := m.enterPending(); err != nil {
+		return err
+	}
+	if node.Status != "unchanged" {
+		m.changed = true
+	}
+	return m.out.WriteNode(path, node)
+}
+
+func (m *streamMerger) enterPending() error {
+	for m.entered < len(m.pending) {
+		parents := m.pending[:m.entered]
+		if err := m.out.EnterNested(parents, m.pending[m.entered]); err != nil {
+			return err
+		}
+		m.entered++
+	}
+	return nil
+}
+
+func (m *streamMerger) leave(path []string, key string) error {
+	m.pending = m.pending[:len(m.pending)-1]
+	if m.entered > len(m.pending) {
+		m.entered = len(m.pending)
+		return m.out.LeaveNested(path, key)
+	}
+	return nil
+}
+
+func (m *streamMerger) wanted(path []string, key string) bool {
+	nodePath := strings.Join(append(append([]string(nil), path...), key), ".")
+	if isIgnored(nodePath, m.opts.Ignore) {
+		return false
+	}
+	return len(m.opts.Only) == 0 || isSelected(nodePath, m.opts.Only) || isUnderSelected(nodePath, m.opts.Only)
+}
+
+func (m *streamMerger) hasWantedChildren(path []string, key string) bool {
+	nodePath := strings.Join(append(append([]string(nil), path...), key), ".")
+	return !isIgnored(nodePath, m.opts.Ignore) && isAncestor(nodePath, m.opts.Only)
+}
+
+func readHead(dec *json.Decoder) (valueHead, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return valueHead{}, err
+	}
+
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return valueHead{kind: scalarValue, value: token}, nil
+	}
+
+	kind := objectValue
+	if delim == '[' {
+		kind = arrayValue
+	}
+	return valueHead{kind: kind, empty: !dec.More()}, nil
+}
+
+func (h valueHead) isLeaf() bool {
+	return h.kind == scalarValue || h.empty
+}
+
+func finishValue(dec *json.Decoder, head valueHead) (interface{}, error) {
+	if head.empty {
+		_, err := dec.Token()
+		return nil, err
+	}
+
+	switch head.kind {
+	case objectValue:
+		object := map[string]interface{}{}
+		for dec.More() {
+			key, _, err := nextKey(dec, "")
+			if err != nil {
+				return nil, err
+			}
+			var value interface{}
+			if err := dec.Decode(&value); err != nil {
+				return nil, err
+			}
+			object[key] = value
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return object, nil
+	case arrayValue:
+		var array []interface{}
+		for dec.More() {
+			var value interface{}
+			if err := dec.Decode(&value); err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return array, nil
+	default:
+		return head.value, nil
+	}
+}
+
+func readValue(dec *json.Decoder) (interface{}, error) {
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	return treeValue(value), nil
+}
+
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if delim, ok := token.(json.Delim); ok {
+			if delim == '{' || delim == '[' {
+				depth++
+			} else {
+				depth--
+			}
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+func treeValue(value interface{}) interface{} {
+	node := valueTree(value)
+	if hasChildren(node) {
+		return reconstructObject(node)
+	}
+	return node.Value
+}
+
+func valueTree(value interface{}) *models.TreeNode {
+	return СonvertMapToTree(map[string]interface{}{"value": value}).Child("value")
+}
+
+func nextKey(dec *json.Decoder, previous string) (string, bool, error) {
+	if !dec.More() {
+		return "", false, nil
+	}
+
+	token, err := dec.Token()
+	if err != nil {
+		return "", false, err
+	}
+	key, ok := token.(string)
+	if !ok {
+		return "", false, fmt.Errorf("expected an object key, got %v", token)
+	}
+	if previous != "" && key <= previous {
+		return "", false, fmt.Errorf("%w: %q comes after %q", errUnsorted, key, previous)
+	}
+	return key, true, nil
+}
+
+func lessKey(a, b string) bool {
+	x, errA := strconv.ParseFloat(keyValue(a), 64)
+	y, errB := strconv.ParseFloat(keyValue(b), 64)
+	if errA == nil && errB == nil {
+		return x < y
+	}
+	return a < b
+}
+
+func keyValue(key string) string {
+	_, value, _ := strings.Cut(strings.TrimSuffix(key, "]"), "=")
+	return value
+}
@@ -1,24 +1,18 @@
 package parsers
 
 import (
-	"code/internal/models"
 	"fmt"
+	"github.com/jobsboris27/go-project-244/internal/models"
 	"sort"
 )
 
 func СonvertMapToTree(data map[string]interface{}) *models.TreeNode {
-	root := &models.TreeNode{Key: "root", Children: []*models.TreeNode{}}
+	children := []*models.TreeNode{}
 
 	for key, value := range data {
 		switch v := value.(type) {
 		case map[string]interface{}:
-			childTree := СonvertMapToTree(v)
-			childNode := &models.TreeNode{
-				Key:      key,
-				Value:    nil,
-				Children: childTree.Children,
-			}
-			root.Children = append(root.Children, childNode)
+			children = append(children, models.NewTreeNode(key, nil, СonvertMapToTree(v).Children))
 
 		case map[interface{}]interface{}:
 			convertedMap := make(map[string]interface{})
@@ -27,68 +21,53 @@ func СonvertMapToTree(data map[string]interface{}) *models.TreeNode {
 					convertedMap[strKey] = val
 				}
 			}
-			childTree := СonvertMapToTree(convertedMap)
-			childNode := &models.TreeNode{
-				Key:      key,
-				Value:    nil,
-				Children: childTree.Children,
-			}
-			root.Children = append(root.Children, childNode)
+			children = append(children, models.NewTreeNode(key, nil, СonvertMapToTree(convertedMap).Children))
 
 		case []interface{}:
-			arrayNode := &models.TreeNode{
-				Key:      key,
-				Value:    nil,
-				Children: []*models.TreeNode{},
-			}
+			items := []*models.TreeNode{}
 
 			for i, item := range v {
-				itemNode := &models.TreeNode{
-					Key:   fmt.Sprintf("[%d]", i),
-					Value: item,
-				}
+				var itemNode *models.TreeNode
 
 				switch v := item.(type) {
 				case map[string]interface{}:
-					itemNode = СonvertMapToTree(v)
-					itemNode.Key = fmt.Sprintf("[%d]", i)
+					itemNode = models.NewTreeNode(fmt.Sprintf("[%d]", i), nil, СonvertMapToTree(v).Children)
 				case []interface{}:
 					itemNode = СonvertMapToTree(map[string]interface{}{
 						fmt.Sprintf("[%d]", i): v,
 					})
 				default:
-					itemNode = &models.TreeNode{
-						Key:   fmt.Sprintf("[%d]", i),
-						Value: item,
-					}
+					itemNode = models.NewTreeNode(fmt.Sprintf("[%d]", i), item, nil)
 				}
 
-				arrayNode.Children = append(arrayNode.Children, itemNode)
+				items = append(items, itemNode)
 			}
-			root.Children = append(root.Children, arrayNode)
+			children = append(children, models.NewTreeNode(key, nil, items))
 		default:
-			leafNode := &models.TreeNode{
-				Key:   key,
-				Value: v,
-			}
-			root.Children = append(root.Children, leafNode)
+			children = append(children, models.NewTreeNode(key, v, nil))
 		}
 	}
 
-	return root
+	return models.NewTreeNode("root", nil, children)
 }
 
-func GetDiff(tree1, tree2 *models.TreeNode) []*models.DiffNode {
-	var diff []*models.DiffNode
+func GetDiff(tree1, tree2 *models.TreeNode) models.Diff {
+	var diff models.Diff
 
 	allKeys := collectAllKeys(tree1, tree2)
 	sort.Strings(allKeys)
 
 	for _, key := range allKeys {
-		node1 := findChildByKey(tree1, key)
-		node2 := findChildByKey(tree2, key)
+		node1 := tree1.Child(key)
+		node2 := tree2.Child(key)
 
 		diffNode := &models.DiffNode{Key: key}
+		if node1 != nil {
+			diffNode.OldPosition = node1.Position
+		}
+		if node2 != nil {
+			diffNode.NewPosition = node2.Position
+		}
 
 		switch {
 		case node1 == nil && node2 != nil:
@@ -108,7 +87,10 @@ func GetDiff(tree1, tree2 *models.TreeNode) []*models.DiffNode {
 			}
 
 		case node1 != nil && node2 != nil:
-			if hasChildren(node1) && hasChildren(node2) {
+			if hasChildren(node1) && hasChildren(node2) && node1.Hash() == node2.Hash() {
+				diffNode.Status = "nested"
+				diffNode.Children = unchangedDiff(node1, node2)
+			} else if hasChildren(node1) && hasChildren(node2) {
 				diffNode.Status = "nested"
 				diffNode.Children = GetDiff(node1, node2)
 			} else if !hasChildren(node1) && !hasChildren(node2) && areValuesEqual(node1, node2) {
@@ -135,6 +117,25 @@ func GetDiff(tree1, tree2 *models.TreeNode) []*models.DiffNode {
 	return diff
 }
 
+func unchangedDiff(tree1, tree2 *models.TreeNode) models.Diff {
+	diff := make(models.Diff, 0, len(tree1.Children))
+
+	for i, node1 := range tree1.Children {
+		node2 := tree2.Children[i]
+		diffNode := &models.DiffNode{Key: node1.Key, OldPosition: node1.Position, NewPosition: node2.Position}
+		if hasChildren(node1) {
+			diffNode.Status = "nested"
+			diffNode.Children = unchangedDiff(node1, node2)
+		} else {
+			diffNode.Status = "unchanged"
+			diffNode.OldValue = node1.Value
+		}
+		diff = append(diff, diffNode)
+	}
+
+	return diff
+}
+
 func collectAllKeys(tree1, tree2 *models.TreeNode) []string {
 	keys := make(map[string]bool)
 
@@ -156,18 +157,6 @@ func collectAllKeys(tree1, tree2 *models.TreeNode) []string {
 	return result
 }
 
-func findChildByKey(tree *models.TreeNode, key string) *models.TreeNode {
-	if tree == nil {
-		return nil
-	}
-	for _, child := range tree.Children {
-		if child.Key == key {
-			return child
-		}
-	}
-	return nil
-}
-
 func areValuesEqual(node1, node2 *models.TreeNode) bool {
 	if node1 == nil || node2 == nil {
 		return false
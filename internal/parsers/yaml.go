@@ -1,14 +1,19 @@
 package parsers
 
 import (
+	"bytes"
 	"fmt"
+	"github.com/jobsboris27/go-project-244/internal/models"
+	"io"
 	"os"
 
-	"github.com/stretchr/testify/assert/yaml"
+	"gopkg.in/yaml.v3"
 )
 
-func ParseYAML(path string) (map[string]interface{}, error) {
-	data, err := os.ReadFile(path)
+type yamlParser struct{}
+
+func (yamlParser) Parse(r io.Reader) (any, error) {
+	data, err := io.ReadAll(r)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
@@ -17,23 +22,64 @@ func ParseYAML(path string) (map[string]interface{}, error) {
 	if err := yaml.Unmarshal(data, &raw); err != nil {
 		return nil, fmt.Errorf("failed to parse YAML: %w", err)
 	}
+	return raw, nil
+}
+
+func (yamlParser) Extensions() []string {
+	return []string{YAML_EXT, YAML_EXT_SHORT}
+}
+
+func (yamlParser) MIMETypes() []string {
+	return []string{"application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml"}
+}
+
+func (yamlParser) Sniff(head []byte) bool {
+	return bytes.HasPrefix(head, []byte("---")) || bytes.HasPrefix(head, []byte("%YAML"))
+}
 
-	switch v := raw.(type) {
-	case map[interface{}]interface{}:
-		converted := make(map[string]interface{})
-		for k, val := range v {
-			if strKey, ok := k.(string); ok {
-				converted[strKey] = val
-			} else {
-				converted[fmt.Sprintf("%v", k)] = val
-			}
+func ParseYAML(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	return ParseReader(bytes.NewReader(data), yamlParser{})
+}
+
+func (yamlParser) Locate(data []byte) (map[string]models.Position, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+
+	positions := make(map[string]models.Position)
+	if len(document.Content) == 0 {
+		return positions, nil
+	}
+
+	root := document.Content[0]
+	if root.Kind == yaml.MappingNode {
+		locateYAML(root, "", positions)
+	} else {
+		locateYAML(root, "root", positions)
+	}
+	return positions, nil
+}
+
+func locateYAML(node *yaml.Node, path string, positions map[string]models.Position) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			keyPath := joinPath(path, key.Value)
+			positions[keyPath] = models.Position{Line: key.Line, Column: key.Column}
+			locateYAML(value, keyPath, positions)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			itemPath := joinPath(path, fmt.Sprintf("[%d]", i))
+			positions[itemPath] = models.Position{Line: item.Line, Column: item.Column}
+			locateYAML(item, itemPath, positions)
 		}
-		return converted, nil
-	case map[string]interface{}:
-		return v, nil
-	case []interface{}:
-		return map[string]interface{}{"root": v}, nil
-	default:
-		return map[string]interface{}{"root": v}, nil
 	}
 }
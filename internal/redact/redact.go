@@ -0,0 +1,263 @@
+package redact
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/jobsboris27/go-project-244/internal/models"
+	parsers "github.com/jobsboris27/go-project-244/internal/parsers"
+	"path"
+	"regexp"
+	"strings"
+)
+
+const (
+	Mask       = "***"
+	hashLength = 8
+)
+
+var DefaultKeyPatterns = []string{
+	"*password*",
+	"*passwd*",
+	"*secret*",
+	"*token*",
+	"*apikey*",
+	"*api_key*",
+	"*private_key*",
+	"*credential*",
+}
+
+var defaultValuePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`),
+	regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+	regexp.MustCompile(`-----BEGIN [A-Z0-9 ]+-----`),
+}
+
+type Rules struct {
+	Detect bool
+	Paths  []string
+	Key    []byte
+}
+
+type Redactor struct {
+	rules Rules
+	key   []byte
+}
+
+func New(rules Rules) *Redactor {
+	r := &Redactor{rules: rules, key: rules.Key}
+	if len(r.key) > 0 || !r.Enabled() {
+		return r
+	}
+
+	r.key = make([]byte, 32)
+	if _, err := rand.Read(r.key); err != nil {
+		panic(fmt.Sprintf("redact: reading random key: %v", err))
+	}
+	return r
+}
+
+func (r *Redactor) Enabled() bool {
+	return r.rules.Detect || len(r.rules.Paths) > 0
+}
+
+func (r *Redactor) Redact(diff models.Diff) models.Diff {
+	if !r.Enabled() {
+		return diff
+	}
+	return r.redactNodes(diff, "")
+}
+
+func (r *Redactor) RedactMatrix(matrix *models.Matrix) *models.Matrix {
+	if !r.Enabled() {
+		return matrix
+	}
+
+	redacted := *matrix
+	redacted.Rows = make([]*models.MatrixRow, 0, len(matrix.Rows))
+	for _, row := range matrix.Rows {
+		key := row.Path[strings.LastIndex(row.Path, ".")+1:]
+		masked := *row
+		masked.Cells = make([]models.MatrixCell, len(row.Cells))
+		for i, cell := range row.Cells {
+			masked.Cells[i] = cell
+			if !cell.Present {
+				continue
+			}
+			if r.isSecretKey(row.Path, key) {
+				masked.Cells[i].Value = r.mask(cell.Value)
+			} else {
+				masked.Cells[i].Value = r.redactValue(cell.Value, row.Path)
+			}
+		}
+		redacted.Rows = append(redacted.Rows, &masked)
+	}
+	return &redacted
+}
+
+func (r *Redactor) redactNodes(diffNodes []*models.DiffNode, prefix string) []*models.DiffNode {
+	result := make([]*models.DiffNode, 0, len(diffNodes))
+
+	for _, node := range diffNodes {
+		nodePath := joinPath(prefix, node.Key)
+		redacted := *node
+
+		if r.isSecretKey(nodePath, node.Key) {
+			redacted.OldValue = r.mask(node.OldValue)
+			redacted.NewValue = r.mask(node.NewValue)
+			if node.Status == "nested" {
+				redacted.Children = r.maskAll(node.Children)
+			}
+		} else {
+			redacted.OldValue = r.redactValue(node.OldValue, nodePath)
+			redacted.NewValue = r.redactValue(node.NewValue, nodePath)
+			redacted.Children = r.redactNodes(node.Children, nodePath)
+		}
+
+		result = append(result, &redacted)
+	}
+
+	return result
+}
+
+func (r *Redactor) maskAll(diffNodes []*models.DiffNode) []*models.DiffNode {
+	result := make([]*models.DiffNode, 0, len(diffNodes))
+	for _, node := range diffNodes {
+		redacted := *node
+		redacted.OldValue = r.mask(node.OldValue)
+		redacted.NewValue = r.mask(node.NewValue)
+		redacted.Children = r.maskAll(node.Children)
+		result = append(result, &redacted)
+	}
+	return result
+}
+
+func (r *Redactor) redactValue(value interface{}, valuePath string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			itemPath := joinPath(valuePath, key)
+			if r.isSecretKey(itemPath, key) {
+				result[key] = r.mask(item)
+			} else {
+				result[key] = r.redactValue(item, itemPath)
+			}
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			itemPath := joinPath(valuePath, fmt.Sprintf("[%d]", i))
+			if r.isSecretKey(itemPath, "") {
+				result[i] = r.mask(item)
+			} else {
+				result[i] = r.redactValue(item, itemPath)
+			}
+		}
+		return result
+	case string:
+		if r.rules.Detect && isSecretValue(v) {
+			return r.mask(v)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func (r *Redactor) isSecretKey(keyPath, key string) bool {
+	for _, pattern := range r.rules.Paths {
+		if parsers.MatchPath(pattern, keyPath) {
+			return true
+		}
+	}
+
+	if !r.rules.Detect || key == "" {
+		return false
+	}
+	lower := strings.ToLower(key)
+	for _, pattern := range DefaultKeyPatterns {
+		if matchKey(pattern, lower) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchKey(pattern, key string) bool {
+	if inner, ok := strings.CutPrefix(pattern, "*"); ok {
+		if inner, ok = strings.CutSuffix(inner, "*"); ok && !strings.ContainsAny(inner, `*?[\`) {
+			return strings.Contains(key, inner)
+		}
+	}
+	ok, _ := path.Match(pattern, key)
+	return ok
+}
+
+func (r *Redactor) mask(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	mac := hmac.New(sha256.New, r.key)
+	fmt.Fprintf(mac, "%T:%v", value, value)
+	return Mask + hex.EncodeToString(mac.Sum(nil))[:hashLength]
+}
+
+func isSecretValue(value string) bool {
+	for _, pattern := range defaultValuePatterns {
+		if pattern.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func (r *Redactor) RedactNode(parents []string, node *models.DiffNode) *models.DiffNode {
+	if !r.Enabled() {
+		return node
+	}
+
+	prefix := ""
+	for _, key := range parents {
+		prefix = joinPath(prefix, key)
+		if r.isSecretKey(prefix, key) {
+			return r.maskAll([]*models.DiffNode{node})[0]
+		}
+	}
+	return r.redactNodes([]*models.DiffNode{node}, prefix)[0]
+}
+
+func (r *Redactor) Writer(out models.NodeWriter) models.NodeWriter {
+	if !r.Enabled() {
+		return out
+	}
+	return redactingWriter{redactor: r, out: out}
+}
+
+type redactingWriter struct {
+	redactor *Redactor
+	out      models.NodeWriter
+}
+
+func (w redactingWriter) EnterNested(parents []string, key string) error {
+	return w.out.EnterNested(parents, key)
+}
+
+func (w redactingWriter) WriteNode(parents []string, node *models.DiffNode) error {
+	return w.out.WriteNode(parents, w.redactor.RedactNode(parents, node))
+}
+
+func (w redactingWriter) LeaveNested(parents []string, key string) error {
+	return w.out.LeaveNested(parents, key)
+}
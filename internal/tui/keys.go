@@ -0,0 +1,92 @@
+package tui
+
+import (
+	"bufio"
+	"unicode/utf8"
+)
+
+type Key string
+
+const (
+	KeyUp        Key = "up"
+	KeyDown      Key = "down"
+	KeyLeft      Key = "left"
+	KeyRight     Key = "right"
+	KeyPageUp    Key = "pgup"
+	KeyPageDown  Key = "pgdown"
+	KeyHome      Key = "home"
+	KeyEnd       Key = "end"
+	KeyEnter     Key = "enter"
+	KeyEscape    Key = "esc"
+	KeyBackspace Key = "backspace"
+	KeyCtrlC     Key = "ctrl+c"
+)
+
+var escapeSequences = map[string]Key{
+	"[A":  KeyUp,
+	"[B":  KeyDown,
+	"[C":  KeyRight,
+	"[D":  KeyLeft,
+	"OA":  KeyUp,
+	"OB":  KeyDown,
+	"OC":  KeyRight,
+	"OD":  KeyLeft,
+	"[H":  KeyHome,
+	"[F":  KeyEnd,
+	"[1~": KeyHome,
+	"[4~": KeyEnd,
+	"[5~": KeyPageUp,
+	"[6~": KeyPageDown,
+}
+
+func ReadKey(r *bufio.Reader) (Key, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return "", err
+	}
+
+	switch b {
+	case 3:
+		return KeyCtrlC, nil
+	case '\r', '\n':
+		return KeyEnter, nil
+	case 127, 8:
+		return KeyBackspace, nil
+	case 27:
+		return readEscape(r)
+	}
+
+	if b < utf8.RuneSelf {
+		return Key(string(rune(b))), nil
+	}
+	if err := r.UnreadByte(); err != nil {
+		return "", err
+	}
+	ch, _, err := r.ReadRune()
+	if err != nil {
+		return "", err
+	}
+	return Key(string(ch)), nil
+}
+
+func readEscape(r *bufio.Reader) (Key, error) {
+	if r.Buffered() == 0 {
+		return KeyEscape, nil
+	}
+
+	sequence := ""
+	for r.Buffered() > 0 && len(sequence) < 4 {
+		b, err := r.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		sequence += string(rune(b))
+		if key, ok := escapeSequences[sequence]; ok {
+			return key, nil
+		}
+		if len(sequence) > 1 && (b >= 'A' && b <= 'Z' || b == '~') {
+			break
+		}
+	}
+	return KeyEscape, nil
+}
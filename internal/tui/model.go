@@ -0,0 +1,545 @@
+package tui
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/jobsboris27/go-project-244/internal/models"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	colorReset   = "\033[0m"
+	colorRed     = "\033[31m"
+	colorGreen   = "\033[32m"
+	colorYellow  = "\033[33m"
+	colorDim     = "\033[2m"
+	colorReverse = "\033[7m"
+)
+
+var statusFilters = []string{"", "added", "removed", "modified"}
+
+type row struct {
+	node  *models.DiffNode
+	path  string
+	depth int
+}
+
+type Action struct {
+	Quit bool
+	Copy string
+}
+
+type Model struct {
+	roots         []*models.DiffNode
+	expanded      map[string]bool
+	showUnchanged bool
+	filter        string
+	query         string
+	searching     bool
+	message       string
+
+	rows   []row
+	cursor int
+	offset int
+	width  int
+	height int
+}
+
+func New(diffNodes []*models.DiffNode) *Model {
+	m := &Model{
+		roots:    diffNodes,
+		expanded: map[string]bool{},
+		width:    80,
+		height:   24,
+	}
+	m.refresh("")
+	return m
+}
+
+func (m *Model) SetSize(width, height int) {
+	if width > 0 {
+		m.width = width
+	}
+	if height > 1 {
+		m.height = height
+	}
+	m.scroll()
+}
+
+func (m *Model) Update(key Key) Action {
+	m.message = ""
+	if m.searching {
+		return m.updateSearch(key)
+	}
+
+	switch key {
+	case "q", KeyCtrlC:
+		return Action{Quit: true}
+	case "j", KeyDown:
+		m.move(1)
+	case "k", KeyUp:
+		m.move(-1)
+	case KeyPageDown, " ":
+		m.move(m.pageSize())
+	case KeyPageUp, "b":
+		m.move(-m.pageSize())
+	case "g", KeyHome:
+		m.move(-len(m.rows))
+	case "G", KeyEnd:
+		m.move(len(m.rows))
+	case "l", KeyRight:
+		m.expand(true)
+	case KeyEnter:
+		if r, ok := m.current(); ok {
+			m.expand(!m.expanded[r.path])
+		}
+	case "h", KeyLeft:
+		m.collapse()
+	case "E":
+		m.expandAll(m.roots, "")
+		m.refresh(m.currentPath())
+	case "C":
+		m.expanded = map[string]bool{}
+		m.refresh(rootOf(m.currentPath()))
+	case "n":
+		m.jumpChange(1)
+	case "N":
+		m.jumpChange(-1)
+	case "f":
+		m.filter = nextFilter(m.filter)
+		m.refresh(m.currentPath())
+	case "u":
+		m.showUnchanged = !m.showUnchanged
+		m.refresh(m.currentPath())
+	case "/":
+		m.searching = true
+	case KeyEscape:
+		if m.query != "" {
+			m.query = ""
+			m.refresh(m.currentPath())
+		}
+	case "y":
+		if r, ok := m.current(); ok {
+			m.message = "copied path"
+			return Action{Copy: r.path}
+		}
+	case "Y":
+		if r, ok := m.current(); ok {
+			m.message = "copied value"
+			return Action{Copy: copyValue(r.node)}
+		}
+	}
+	return Action{}
+}
+
+func (m *Model) updateSearch(key Key) Action {
+	switch key {
+	case KeyCtrlC:
+		return Action{Quit: true}
+	case KeyEnter:
+		m.searching = false
+	case KeyEscape:
+		m.searching = false
+		m.query = ""
+	case KeyBackspace:
+		if m.query != "" {
+			_, size := utf8.DecodeLastRuneInString(m.query)
+			m.query = m.query[:len(m.query)-size]
+		}
+	default:
+		if utf8.RuneCountInString(string(key)) == 1 {
+			m.query += string(key)
+		}
+	}
+	m.refresh(m.currentPath())
+	if m.query != "" {
+		m.selectFirstMatch()
+	}
+	return Action{}
+}
+
+func (m *Model) selectFirstMatch() {
+	for i, r := range m.rows {
+		if r.node.Status != "nested" && m.matches(r.path) {
+			m.cursor = i
+			m.scroll()
+			return
+		}
+	}
+}
+
+func (m *Model) View() string {
+	var b strings.Builder
+	listHeight := m.pageSize()
+
+	for i := m.offset; i < m.offset+listHeight; i++ {
+		if i < len(m.rows) {
+			line := fit(m.rowText(m.rows[i]), m.width)
+			if i == m.cursor {
+				b.WriteString(colorReverse + line + strings.Repeat(" ", m.width-utf8.RuneCountInString(line)) + colorReset)
+			} else {
+				b.WriteString(m.paint(m.rows[i].node.Status, line))
+			}
+		}
+		b.WriteString("\033[K\r\n")
+	}
+
+	b.WriteString(colorReverse + m.statusLine() + colorReset)
+	return b.String()
+}
+
+func (m *Model) Path() string {
+	return m.currentPath()
+}
+
+func (m *Model) rowText(r row) string {
+	indent := strings.Repeat("  ", r.depth)
+	node := r.node
+	key := sanitize(node.Key)
+
+	switch node.Status {
+	case "nested":
+		marker := "▸"
+		if m.isExpanded(r.path) {
+			marker = "▾"
+		}
+		return fmt.Sprintf("%s%s %s", indent, marker, key)
+	case "added":
+		return fmt.Sprintf("%s+ %s: %s", indent, key, valueText(node.NewValue))
+	case "removed":
+		return fmt.Sprintf("%s- %s: %s", indent, key, valueText(node.OldValue))
+	case "modified":
+		return fmt.Sprintf("%s~ %s: %s → %s", indent, key, valueText(node.OldValue), valueText(node.NewValue))
+	default:
+		return fmt.Sprintf("%s  %s: %s", indent, key, valueText(node.OldValue))
+	}
+}
+
+func (m *Model) statusLine() string {
+	left := sanitize(m.currentPath())
+	if m.searching {
+		left = "/" + sanitize(m.query) + "█"
+	} else if m.message != "" {
+		left = m.message
+	}
+
+	var flags []string
+	if m.filter != "" {
+		flags = append(flags, "only "+m.filter)
+	}
+	if !m.showUnchanged {
+		flags = append(flags, "unchanged hidden")
+	}
+	if m.query != "" && !m.searching {
+		flags = append(flags, fmt.Sprintf("search %q", m.query))
+	}
+	flags = append(flags, fmt.Sprintf("%d/%d", min(m.cursor+1, len(m.rows)), len(m.rows)))
+
+	right := strings.Join(flags, " · ") + "  n/N change  f filter  u unchanged  / search  y/Y copy  q quit"
+	line := left + "  " + right
+	line = fit(line, m.width)
+	return line + strings.Repeat(" ", max(m.width-utf8.RuneCountInString(line), 0))
+}
+
+func (m *Model) paint(status, text string) string {
+	switch status {
+	case "added":
+		return colorGreen + text + colorReset
+	case "removed":
+		return colorRed + text + colorReset
+	case "modified":
+		return colorYellow + text + colorReset
+	case "unchanged":
+		return colorDim + text + colorReset
+	default:
+		return text
+	}
+}
+
+func (m *Model) refresh(keepPath string) {
+	m.rows = m.rows[:0]
+	m.flatten(m.roots, "", 0)
+
+	m.cursor = 0
+	for i, r := range m.rows {
+		if r.path == keepPath {
+			m.cursor = i
+			break
+		}
+		if strings.HasPrefix(keepPath, r.path+".") {
+			m.cursor = i
+		}
+	}
+	m.scroll()
+}
+
+func (m *Model) flatten(diffNodes []*models.DiffNode, prefix string, depth int) {
+	for _, node := range diffNodes {
+		path := joinPath(prefix, node.Key)
+		if !m.visible(node, path) {
+			continue
+		}
+
+		m.rows = append(m.rows, row{node: node, path: path, depth: depth})
+		if node.Status == "nested" && m.isExpanded(path) {
+			m.flatten(node.Children, path, depth+1)
+		}
+	}
+}
+
+func (m *Model) visible(node *models.DiffNode, path string) bool {
+	if node.Status == "nested" {
+		if m.query != "" && m.matches(path) {
+			return true
+		}
+		for _, child := range node.Children {
+			if m.visible(child, joinPath(path, child.Key)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if node.Status == "unchanged" && !m.showUnchanged {
+		return false
+	}
+	if m.filter != "" && node.Status != m.filter {
+		return false
+	}
+	return m.query == "" || m.matches(path)
+}
+
+func (m *Model) matches(path string) bool {
+	return strings.Contains(strings.ToLower(path), strings.ToLower(m.query))
+}
+
+func (m *Model) isExpanded(path string) bool {
+	return m.expanded[path] || m.query != ""
+}
+
+func (m *Model) expand(open bool) {
+	r, ok := m.current()
+	if !ok || r.node.Status != "nested" {
+		return
+	}
+	m.expanded[r.path] = open
+	m.refresh(r.path)
+}
+
+func (m *Model) collapse() {
+	r, ok := m.current()
+	if !ok {
+		return
+	}
+	if r.node.Status == "nested" && m.expanded[r.path] {
+		m.expand(false)
+		return
+	}
+	if i := strings.LastIndex(r.path, "."); i >= 0 {
+		m.refresh(r.path[:i])
+	}
+}
+
+func (m *Model) expandAll(diffNodes []*models.DiffNode, prefix string) {
+	for _, node := range diffNodes {
+		if node.Status == "nested" {
+			path := joinPath(prefix, node.Key)
+			m.expanded[path] = true
+			m.expandAll(node.Children, path)
+		}
+	}
+}
+
+func (m *Model) jumpChange(direction int) {
+	var changes []string
+	m.collectChanges(m.roots, "", &changes)
+	if len(changes) == 0 {
+		m.message = "no changes"
+		return
+	}
+
+	current := m.currentPath()
+	order := m.pathOrder()
+	position := order[current]
+
+	target := ""
+	if direction > 0 {
+		for _, path := range changes {
+			if order[path] > position {
+				target = path
+				break
+			}
+		}
+		if target == "" {
+			target = changes[0]
+		}
+	} else {
+		for i := len(changes) - 1; i >= 0; i-- {
+			if order[changes[i]] < position {
+				target = changes[i]
+				break
+			}
+		}
+		if target == "" {
+			target = changes[len(changes)-1]
+		}
+	}
+
+	for i := strings.Index(target, "."); i >= 0; i = nextDot(target, i) {
+		m.expanded[target[:i]] = true
+	}
+	m.refresh(target)
+}
+
+func (m *Model) collectChanges(diffNodes []*models.DiffNode, prefix string, changes *[]string) {
+	for _, node := range diffNodes {
+		path := joinPath(prefix, node.Key)
+		switch {
+		case node.Status == "nested":
+			m.collectChanges(node.Children, path, changes)
+		case node.Status != "unchanged" && m.visible(node, path):
+			*changes = append(*changes, path)
+		}
+	}
+}
+
+func (m *Model) pathOrder() map[string]int {
+	order := map[string]int{}
+	var walk func([]*models.DiffNode, string)
+	walk = func(diffNodes []*models.DiffNode, prefix string) {
+		for _, node := range diffNodes {
+			path := joinPath(prefix, node.Key)
+			order[path] = len(order) + 1
+			walk(node.Children, path)
+		}
+	}
+	walk(m.roots, "")
+	return order
+}
+
+func (m *Model) move(delta int) {
+	m.cursor = max(0, min(m.cursor+delta, len(m.rows)-1))
+	m.scroll()
+}
+
+func (m *Model) scroll() {
+	page := m.pageSize()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+page {
+		m.offset = m.cursor - page + 1
+	}
+	m.offset = max(0, min(m.offset, len(m.rows)-page))
+}
+
+func (m *Model) pageSize() int {
+	return max(m.height-1, 1)
+}
+
+func (m *Model) current() (row, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return row{}, false
+	}
+	return m.rows[m.cursor], true
+}
+
+func (m *Model) currentPath() string {
+	r, _ := m.current()
+	return r.path
+}
+
+func OSC52(text string) string {
+	return "\033]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+}
+
+func copyValue(node *models.DiffNode) string {
+	value := node.NewValue
+	if node.Status == "removed" || node.Status == "unchanged" {
+		value = node.OldValue
+	}
+	if node.Status == "nested" {
+		return ""
+	}
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+func valueText(value interface{}) string {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return "{…}"
+	case []interface{}:
+		return "[…]"
+	case nil:
+		return "null"
+	case string:
+		return sanitize(v)
+	default:
+		return sanitize(fmt.Sprintf("%v", v))
+	}
+}
+
+func sanitize(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\n':
+			b.WriteString("⏎")
+		case unicode.IsControl(r), r >= 0x202a && r <= 0x202e, r >= 0x2066 && r <= 0x2069:
+			b.WriteString(strings.Trim(strconv.QuoteRuneToASCII(r), "'"))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func nextFilter(current string) string {
+	for i, filter := range statusFilters {
+		if filter == current {
+			return statusFilters[(i+1)%len(statusFilters)]
+		}
+	}
+	return ""
+}
+
+func fit(text string, width int) string {
+	if utf8.RuneCountInString(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:max(width-1, 0)]) + "…"
+}
+
+func rootOf(path string) string {
+	if i := strings.Index(path, "."); i >= 0 {
+		return path[:i]
+	}
+	return path
+}
+
+func nextDot(path string, from int) int {
+	i := strings.Index(path[from+1:], ".")
+	if i < 0 {
+		return -1
+	}
+	return from + 1 + i
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
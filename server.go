@@ -0,0 +1,395 @@
+package gendiff
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jobsboris27/go-project-244/internal/formatters"
+	parsers "github.com/jobsboris27/go-project-244/internal/parsers"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultMaxBodyBytes is the request size limit used when
+	// ServerOptions.MaxBodyBytes is zero.
+	DefaultMaxBodyBytes = 10 << 20
+	// DefaultTimeout bounds one request when ServerOptions.Timeout is zero.
+	DefaultTimeout = 30 * time.Second
+)
+
+// ServerOptions configures NewServer.
+type ServerOptions struct {
+	// MaxBodyBytes caps the size of a POST /diff request body.
+	MaxBodyBytes int64
+	// Timeout bounds the time spent comparing and rendering one request.
+	Timeout time.Duration
+	// MaxConcurrent caps the number of diffs computed at once; zero means
+	// GOMAXPROCS. A request waits for a free slot until its timeout, and a diff
+	// that outlives its request keeps its slot until it finishes, so abandoned
+	// work cannot pile up.
+	MaxConcurrent int
+	// Format is the output format used when a request names none.
+	Format string
+	// Defaults are the comparison options a request starts from; fields the
+	// request sets replace them. Secrets are masked unless
+	// Defaults.ShowSecrets is set or a request sets "redact" to false.
+	Defaults Options
+}
+
+// Server is an http.Handler exposing CompareDocuments as a JSON API:
+//
+//	POST /diff    compare two documents, sent as JSON or multipart/form-data
+//	GET  /healthz liveness probe
+//	GET  /readyz  readiness probe, failing once SetReady(false) is called
+//
+// Errors are returned as {"error": {"code": "...", "message": "..."}}.
+type Server struct {
+	opts  ServerOptions
+	mux   *http.ServeMux
+	work  chan struct{}
+	ready atomic.Bool
+}
+
+// NewServer returns a ready Server.
+func NewServer(opts ServerOptions) *Server {
+	if opts.MaxBodyBytes <= 0 {
+		opts.MaxBodyBytes = DefaultMaxBodyBytes
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.MaxConcurrent <= 0 {
+		opts.MaxConcurrent = runtime.GOMAXPROCS(0)
+	}
+	if opts.Format == "" {
+		opts.Format = formatters.STYLISH
+	}
+
+	s := &Server{opts: opts, mux: http.NewServeMux(), work: make(chan struct{}, opts.MaxConcurrent)}
+	s.ready.Store(true)
+	s.mux.HandleFunc("/diff", s.handleDiff)
+	s.mux.HandleFunc("/healthz", s.handleHealth)
+	s.mux.HandleFunc("/readyz", s.handleReady)
+	s.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("no route for %s", r.URL.Path))
+	})
+	return s
+}
+
+// SetReady changes the /readyz answer, for example while shutting down.
+func (s *Server) SetReady(ready bool) {
+	s.ready.Store(ready)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+type diffRequest struct {
+	Old         *requestDocument  `json:"old"`
+	New         *requestDocument  `json:"new"`
+	Format      string            `json:"format"`
+	Ignore      []string          `json:"ignore"`
+	Only        []string          `json:"only"`
+	Comparators map[string]string `json:"comparators"`
+	Redact      *bool             `json:"redact"`
+	RedactPaths []string          `json:"redactPaths"`
+	Positions   *bool             `json:"positions"`
+}
+
+type requestDocument struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Content string `json:"content"`
+
+	mimeType string
+}
+
+type errorResponse struct {
+	Error errorBody `json:"error"`
+}
+
+type errorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type requestError struct {
+	status int
+	code   string
+	err    error
+}
+
+func (e *requestError) Error() string {
+	return e.err.Error()
+}
+
+var contentTypes = map[string]string{
+	formatters.JSON:     "application/json",
+	formatters.YAML:     "application/yaml",
+	formatters.HTML:     "text/html; charset=utf-8",
+	formatters.MARKDOWN: "text/markdown; charset=utf-8",
+	formatters.SARIF:    "application/sarif+json",
+	formatters.JUNIT:    "application/xml",
+}
+
+func (s *Server) handleDiff(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", "use POST")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, s.opts.MaxBodyBytes)
+	oldDoc, newDoc, format, opts, err := s.readRequest(r)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		var reqErr *requestError
+		switch {
+		case errors.As(err, &maxBytesErr):
+			writeError(w, http.StatusRequestEntityTooLarge, "request_too_large",
+				fmt.Sprintf("request body exceeds %d bytes", maxBytesErr.Limit))
+		case errors.As(err, &reqErr):
+			writeError(w, reqErr.status, reqErr.code, reqErr.Error())
+		default:
+			writeError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		}
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.opts.Timeout)
+	defer cancel()
+
+	select {
+	case s.work <- struct{}{}:
+	case <-ctx.Done():
+		writeError(w, http.StatusServiceUnavailable, "busy",
+			fmt.Sprintf("no diff slot became free within %s", s.opts.Timeout))
+		return
+	}
+
+	type result struct {
+		body    []byte
+		changes bool
+		err     error
+	}
+	done := make(chan result, 1)
+	go func() {
+		defer func() { <-s.work }()
+
+		diff, err := CompareDocuments(oldDoc, newDoc, opts)
+		if err == nil {
+			err = ctx.Err()
+		}
+		if err != nil {
+			done <- result{err: err}
+			return
+		}
+		var body bytes.Buffer
+		err = RenderWithOptions(&body, diff, format, opts)
+		done <- result{body: body.Bytes(), changes: diff.HasChanges(), err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		writeError(w, http.StatusServiceUnavailable, "timeout",
+			fmt.Sprintf("diff did not finish within %s", s.opts.Timeout))
+	case res := <-done:
+		if res.err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "invalid_document", res.err.Error())
+			return
+		}
+		contentType, ok := contentTypes[format]
+		if !ok {
+			contentType = "text/plain; charset=utf-8"
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("X-Gendiff-Changes", strconv.FormatBool(res.changes))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(res.body)
+	}
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	if !s.ready.Load() {
+		writeError(w, http.StatusServiceUnavailable, "not_ready", "server is shutting down")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ready"})
+}
+
+func (s *Server) readRequest(r *http.Request) (Document, Document, string, Options, error) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		mediaType = ""
+	}
+
+	var req diffRequest
+	switch mediaType {
+	case "application/json":
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&req); err != nil {
+			return Document{}, Document{}, "", Options{}, fmt.Errorf("decoding request: %w", err)
+		}
+	case "multipart/form-data":
+		if err := readMultipart(r, &req); err != nil {
+			return Document{}, Document{}, "", Options{}, err
+		}
+	default:
+		return Document{}, Document{}, "", Options{}, &requestError{
+			status: http.StatusUnsupportedMediaType,
+			code:   "unsupported_media_type",
+			err:    errors.New("send application/json or multipart/form-data"),
+		}
+	}
+
+	if req.Old == nil || req.New == nil {
+		return Document{}, Document{}, "", Options{}, errors.New(`both "old" and "new" documents are required`)
+	}
+
+	format := req.Format
+	if format == "" {
+		format = s.opts.Format
+	}
+	if !slices.Contains(formatters.Names(), format) {
+		return Document{}, Document{}, "", Options{}, &requestError{
+			status: http.StatusBadRequest,
+			code:   "unknown_format",
+			err:    fmt.Errorf("unknown format %q (available: %s)", format, strings.Join(formatters.Names(), ", ")),
+		}
+	}
+	if err := parsers.ValidateComparators(req.Comparators); err != nil {
+		return Document{}, Document{}, "", Options{}, err
+	}
+
+	oldDoc := req.Old.document("old")
+	newDoc := req.New.document("new")
+	return oldDoc, newDoc, format, s.options(req, oldDoc.Name, newDoc.Name), nil
+}
+
+func (s *Server) options(req diffRequest, oldName, newName string) Options {
+	opts := s.opts.Defaults
+	if req.Ignore != nil {
+		opts.Ignore = req.Ignore
+	}
+	if req.Only != nil {
+		opts.Only = req.Only
+	}
+	if req.Comparators != nil {
+		opts.Comparators = req.Comparators
+	}
+	if req.Redact != nil {
+		opts.ShowSecrets = !*req.Redact
+	}
+	if req.RedactPaths != nil {
+		opts.RedactPaths = req.RedactPaths
+	}
+	if req.Positions != nil {
+		opts.Positions = *req.Positions
+	}
+	opts.Color = false
+	opts.OldFile = oldName
+	opts.NewFile = newName
+	return opts
+}
+
+func (d *requestDocument) document(name string) Document {
+	if d.Name != "" {
+		name = d.Name
+	}
+	return Document{Name: name, Type: d.Type, MIMEType: d.mimeType, Content: []byte(d.Content)}
+}
+
+func readMultipart(r *http.Request, req *diffRequest) error {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return err
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading multipart body: %w", err)
+		}
+
+		data, err := io.ReadAll(part)
+		if err != nil {
+			return fmt.Errorf("reading part %q: %w", part.FormName(), err)
+		}
+
+		if err := setFormField(req, part, string(data)); err != nil {
+			return err
+		}
+	}
+}
+
+func setFormField(req *diffRequest, part *multipart.Part, value string) error {
+	switch part.FormName() {
+	case "old", "new":
+		doc := &requestDocument{Name: part.FileName(), Content: value, mimeType: part.Header.Get("Content-Type")}
+		if part.FormName() == "old" {
+			req.Old = doc
+		} else {
+			req.New = doc
+		}
+	case "format":
+		req.Format = value
+	case "ignore":
+		req.Ignore = append(req.Ignore, value)
+	case "only":
+		req.Only = append(req.Only, value)
+	case "redactPath":
+		req.RedactPaths = append(req.RedactPaths, value)
+	case "comparator":
+		path, name, ok := strings.Cut(value, "=")
+		if !ok {
+			return fmt.Errorf("comparator %q must look like path=name", value)
+		}
+		if req.Comparators == nil {
+			req.Comparators = map[string]string{}
+		}
+		req.Comparators[path] = name
+	case "redact", "positions":
+		flag, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", part.FormName(), err)
+		}
+		if part.FormName() == "redact" {
+			req.Redact = &flag
+		} else {
+			req.Positions = &flag
+		}
+	default:
+		return fmt.Errorf("unknown form field %q", part.FormName())
+	}
+	return nil
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, errorResponse{Error: errorBody{Code: code, Message: message}})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
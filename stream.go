@@ -0,0 +1,79 @@
+package gendiff
+
+import (
+	"fmt"
+	"github.com/jobsboris27/go-project-244/internal/formatters"
+	parsers "github.com/jobsboris27/go-project-244/internal/parsers"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// StreamOptions controls Stream and StreamFiles.
+type StreamOptions struct {
+	Options
+	// ArrayKey matches array elements by the value of this field, such as
+	// "id", instead of by index. Elements must be objects sorted by it.
+	ArrayKey string
+}
+
+// Stream diffs two JSON documents without loading them into memory and writes
+// the result to w as it goes. Object keys must be sorted in both documents, as
+// encoders such as Go's encoding/json or jq -S produce them; Stream fails at
+// the first key out of order. Memory grows with nesting depth and with the
+// size of the values that are added, removed or modified, not with the size of
+// the documents. Only the formats returned by StreamFormats are supported.
+// Array elements are listed in index order rather than sorted as strings, so
+// arrays longer than ten elements are ordered differently than by Compare.
+// It reports whether the documents differ.
+func Stream(w io.Writer, r1, r2 io.Reader, format string, opts StreamOptions) (bool, error) {
+	writer, err := formatters.NewStreamWriter(w, format, opts.renderOptions())
+	if err != nil {
+		return false, err
+	}
+
+	redactor := opts.redactor()
+	changed, err := parsers.StreamDiff(r1, r2, redactor.Writer(writer), parsers.StreamOptions{
+		Ignore:      opts.Ignore,
+		Only:        opts.Only,
+		Comparators: opts.Comparators,
+		ArrayKey:    opts.ArrayKey,
+	})
+	if err != nil {
+		return changed, err
+	}
+	return changed, writer.Close()
+}
+
+// StreamFiles is Stream for two .json files.
+func StreamFiles(w io.Writer, path1, path2, format string, opts StreamOptions) (bool, error) {
+	for _, path := range []string{path1, path2} {
+		if !strings.EqualFold(filepath.Ext(path), ".json") {
+			return false, fmt.Errorf("streaming supports only .json files, got %s", path)
+		}
+	}
+
+	file1, err := os.Open(path1)
+	if err != nil {
+		return false, err
+	}
+	defer file1.Close()
+
+	file2, err := os.Open(path2)
+	if err != nil {
+		return false, err
+	}
+	defer file2.Close()
+
+	changed, err := Stream(w, file1, file2, format, opts)
+	if err != nil {
+		return changed, fmt.Errorf("streaming %s and %s: %w", path1, path2, err)
+	}
+	return changed, nil
+}
+
+// StreamFormats returns the formats Stream accepts.
+func StreamFormats() []string {
+	return formatters.StreamFormats()
+}